@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	BulkFormatNDJSON = "ndjson"
+	BulkFormatCSV    = "csv"
+
+	maxBulkLineSize      = 1024 * 1024
+	csvMetadataPrefix    = "metadata."
+	csvContentColumn     = "content"
+	csvRecipientColumn   = "recipient_phone_number"
+	csvScheduledAtColumn = "scheduled_at"
+	csvExpiresAtColumn   = "expires_at"
+	csvPriorityColumn    = "priority"
+	csvExternalIDColumn  = "external_id"
+	csvTagsColumn        = "tags"
+	csvTagSeparator      = ";"
+)
+
+var (
+	ErrUnsupportedBulkFormat = errors.New("unsupported bulk import format")
+	ErrInvalidBulkUpload     = errors.New("invalid bulk upload")
+)
+
+type BulkImportConfig struct {
+	BatchSize int `mapstructure:"batchSize"`
+}
+
+// BulkMessageRow is one message of a bulk upload.
+type BulkMessageRow struct {
+	Content              string            `json:"content"`
+	RecipientPhoneNumber string            `json:"recipient_phone_number"`
+	Priority             string            `json:"priority,omitempty"`
+	ScheduledAt          *time.Time        `json:"scheduled_at,omitempty"`
+	ExpiresAt            *time.Time        `json:"expires_at,omitempty"`
+	ExternalID           string            `json:"external_id,omitempty"`
+	Tags                 []string          `json:"tags,omitempty"`
+	Metadata             map[string]string `json:"metadata,omitempty"`
+}
+
+// bulkRowFunc receives every row of an upload with its line number. rowErr is
+// set when the row could not be parsed; reading continues with the next row.
+type bulkRowFunc[T any] func(line int, row T, rowErr error)
+
+// csvRowDecoder builds a row from a CSV record, columns mapping the header
+// names to their position in the record.
+type csvRowDecoder[T any] func(columns map[string]int, record []string) (T, error)
+
+// readBulkRows streams a message upload row by row. It only returns an error
+// when the upload as a whole cannot be read any further.
+func readBulkRows(format string, r io.Reader, fn bulkRowFunc[BulkMessageRow]) error {
+	return readRows(format, r, []string{csvContentColumn, csvRecipientColumn}, csvRecordToRow, fn)
+}
+
+// readRows streams an NDJSON or CSV upload row by row. NDJSON lines are
+// decoded into T as JSON objects. CSV uploads start with a header row that
+// must name every required column, and decode builds T from each record.
+func readRows[T any](format string, r io.Reader, required []string, decode csvRowDecoder[T], fn bulkRowFunc[T]) error {
+	switch format {
+	case BulkFormatNDJSON:
+		return readNDJSONRows(r, fn)
+	case BulkFormatCSV:
+		return readCSVRows(r, required, decode, fn)
+	default:
+		return ErrUnsupportedBulkFormat
+	}
+}
+
+func readNDJSONRows[T any](r io.Reader, fn bulkRowFunc[T]) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxBulkLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var row T
+		if err := json.Unmarshal(raw, &row); err != nil {
+			var empty T
+			fn(line, empty, fmt.Errorf("invalid JSON: %w", err))
+			continue
+		}
+
+		fn(line, row, nil)
+	}
+
+	return scanner.Err()
+}
+
+func readCSVRows[T any](r io.Reader, required []string, decode csvRowDecoder[T], fn bulkRowFunc[T]) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("%w: failed to read CSV header: %s", ErrInvalidBulkUpload, err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return fmt.Errorf("%w: CSV header is missing the %s column", ErrInvalidBulkUpload, column)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			var empty T
+			fn(parseErr.StartLine, empty, parseErr.Err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		row, err := decode(columns, record)
+		fn(line, row, err)
+	}
+}
+
+// csvRecordToRow reads a message row. content and recipient_phone_number are
+// required, scheduled_at and expires_at are optional RFC3339 times, priority
+// and external_id are optional, tags holds semicolon separated tags and every
+// metadata.<key> column becomes a metadata entry.
+func csvRecordToRow(columns map[string]int, record []string) (BulkMessageRow, error) {
+	row := BulkMessageRow{
+		Content:              record[columns[csvContentColumn]],
+		RecipientPhoneNumber: record[columns[csvRecipientColumn]],
+	}
+
+	if i, ok := columns[csvPriorityColumn]; ok {
+		row.Priority = record[i]
+	}
+	if i, ok := columns[csvExternalIDColumn]; ok {
+		row.ExternalID = record[i]
+	}
+	if i, ok := columns[csvTagsColumn]; ok && record[i] != "" {
+		for _, tag := range strings.Split(record[i], csvTagSeparator) {
+			row.Tags = append(row.Tags, strings.TrimSpace(tag))
+		}
+	}
+
+	var err error
+	if row.ScheduledAt, err = csvTimeColumn(columns, record, csvScheduledAtColumn); err != nil {
+		return row, err
+	}
+	if row.ExpiresAt, err = csvTimeColumn(columns, record, csvExpiresAtColumn); err != nil {
+		return row, err
+	}
+
+	for name, i := range columns {
+		key, ok := strings.CutPrefix(name, csvMetadataPrefix)
+		if !ok || key == "" || record[i] == "" {
+			continue
+		}
+
+		if row.Metadata == nil {
+			row.Metadata = make(map[string]string)
+		}
+		row.Metadata[key] = record[i]
+	}
+
+	return row, nil
+}
+
+// csvTimeColumn parses an optional RFC3339 column. An empty or missing column
+// yields nil.
+func csvTimeColumn(columns map[string]int, record []string, column string) (*time.Time, error) {
+	i, ok := columns[column]
+	if !ok || record[i] == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, record[i])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s, expected RFC3339 time", column)
+	}
+
+	return &value, nil
+}
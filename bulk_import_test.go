@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bulkRowResult struct {
+	line   int
+	row    BulkMessageRow
+	rowErr string
+}
+
+func TestReadBulkRows(t *testing.T) {
+	scheduledAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		format   string
+		body     string
+		wantRows []bulkRowResult
+		wantErr  error
+	}{
+		{
+			name:   "should read NDJSON rows and report malformed lines",
+			format: BulkFormatNDJSON,
+			body: `{"content":"Hello","recipient_phone_number":"+15553579024","scheduled_at":"2025-06-01T09:00:00Z","metadata":{"order":"42"}}
+
+not json
+{"content":"Bye","recipient_phone_number":"+15553579025"}
+`,
+			wantRows: []bulkRowResult{
+				{line: 1, row: BulkMessageRow{Content: "Hello", RecipientPhoneNumber: "+15553579024", ScheduledAt: &scheduledAt, Metadata: map[string]string{"order": "42"}}},
+				{line: 3, rowErr: "invalid JSON: invalid character 'o' in literal null (expecting 'u')"},
+				{line: 4, row: BulkMessageRow{Content: "Bye", RecipientPhoneNumber: "+15553579025"}},
+			},
+		},
+		{
+			name:   "should read CSV rows with metadata columns and report bad rows",
+			format: BulkFormatCSV,
+			body: `content,recipient_phone_number,scheduled_at,metadata.order
+Hello,+15553579024,2025-06-01T09:00:00Z,42
+Bye,+15553579025,,
+Late,+15553579026,tomorrow,43
+Short,+15553579027
+`,
+			wantRows: []bulkRowResult{
+				{line: 2, row: BulkMessageRow{Content: "Hello", RecipientPhoneNumber: "+15553579024", ScheduledAt: &scheduledAt, Metadata: map[string]string{"order": "42"}}},
+				{line: 3, row: BulkMessageRow{Content: "Bye", RecipientPhoneNumber: "+15553579025"}},
+				{line: 4, row: BulkMessageRow{Content: "Late", RecipientPhoneNumber: "+15553579026"}, rowErr: "invalid scheduled_at, expected RFC3339 time"},
+				{line: 5, rowErr: "wrong number of fields"},
+			},
+		},
+		{
+			name:   "should read CSV priority and expiry columns",
+			format: BulkFormatCSV,
+			body: `content,recipient_phone_number,priority,expires_at
+Hello,+15553579024,low,2025-06-01T09:00:00Z
+Bye,+15553579025,,
+`,
+			wantRows: []bulkRowResult{
+				{line: 2, row: BulkMessageRow{Content: "Hello", RecipientPhoneNumber: "+15553579024", Priority: PriorityLow, ExpiresAt: &scheduledAt}},
+				{line: 3, row: BulkMessageRow{Content: "Bye", RecipientPhoneNumber: "+15553579025"}},
+			},
+		},
+		{
+			name:   "should read CSV external ID and tags columns",
+			format: BulkFormatCSV,
+			body: `content,recipient_phone_number,external_id,tags
+Hello,+15553579024,order-42,checkout; eu
+Bye,+15553579025,,
+`,
+			wantRows: []bulkRowResult{
+				{line: 2, row: BulkMessageRow{Content: "Hello", RecipientPhoneNumber: "+15553579024", ExternalID: "order-42", Tags: []string{"checkout", "eu"}}},
+				{line: 3, row: BulkMessageRow{Content: "Bye", RecipientPhoneNumber: "+15553579025"}},
+			},
+		},
+		{
+			name:    "should reject CSV without required columns",
+			format:  BulkFormatCSV,
+			body:    "content,scheduled_at\nHello,\n",
+			wantErr: ErrInvalidBulkUpload,
+		},
+		{
+			name:    "should reject unsupported format",
+			format:  "xml",
+			body:    "<messages/>",
+			wantErr: ErrUnsupportedBulkFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []bulkRowResult
+			err := readBulkRows(tt.format, strings.NewReader(tt.body), func(line int, row BulkMessageRow, rowErr error) {
+				result := bulkRowResult{line: line, row: row}
+				if rowErr != nil {
+					result.rowErr = rowErr.Error()
+				}
+				got = append(got, result)
+			})
+
+			assert.True(t, errors.Is(err, tt.wantErr), "got error %v", err)
+			assert.Equal(t, tt.wantRows, got)
+		})
+	}
+}
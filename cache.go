@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -33,6 +34,30 @@ func (c *RedisCache) Set(ctx context.Context, key string, value string) error {
 	return c.client.Set(ctx, key, value, c.config.TTL).Err()
 }
 
+// SetWithTTL sets the key with its own expiry instead of the configured TTL.
+func (c *RedisCache) SetWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX sets the key only if it does not exist yet and reports whether it did.
+func (c *RedisCache) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Get returns the value of the key, or an empty string if the key does not exist.
+func (c *RedisCache) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+
+	return value, err
+}
+
+func (c *RedisCache) Del(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
@@ -124,3 +124,77 @@ func TestRedisCache_Set(t *testing.T) {
 		})
 	}
 }
+
+func TestRedisCache_SendGuard(t *testing.T) {
+	ctx := context.Background()
+
+	container, redisURL := setupRedisContainer(t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: redisURL,
+	})
+	defer client.Close()
+
+	cache := &RedisCache{
+		client: client,
+		config: CacheConfig{TTL: time.Minute},
+	}
+
+	acquired, err := cache.SetNX(ctx, "send-guard:1", "worker-1", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = cache.SetNX(ctx, "send-guard:1", "worker-2", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	value, err := cache.Get(ctx, "send-guard:1")
+	assert.NoError(t, err)
+	assert.Equal(t, "worker-1", value)
+
+	assert.NoError(t, cache.Del(ctx, "send-guard:1"))
+
+	value, err = cache.Get(ctx, "send-guard:1")
+	assert.NoError(t, err)
+	assert.Empty(t, value)
+
+	acquired, err = cache.SetNX(ctx, "send-guard:1", "worker-2", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestRedisCache_SetWithTTL(t *testing.T) {
+	ctx := context.Background()
+
+	container, redisURL := setupRedisContainer(t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: redisURL,
+	})
+	defer client.Close()
+
+	cache := &RedisCache{
+		client: client,
+		config: CacheConfig{TTL: time.Hour},
+	}
+
+	assert.NoError(t, cache.SetWithTTL(ctx, "stats:1h0m0s", `{"counts":{}}`, 5*time.Second))
+
+	value, err := cache.Get(ctx, "stats:1h0m0s")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"counts":{}}`, value)
+
+	ttl, err := client.TTL(ctx, "stats:1h0m0s").Result()
+	assert.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= 5*time.Second)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CampaignService interface {
+	CreateCampaign(ctx context.Context, req CreateCampaignRequest) (primitive.ObjectID, error)
+	RetrieveCampaign(ctx context.Context, campaignID primitive.ObjectID) (*CampaignProgress, error)
+	PauseCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error)
+	ResumeCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error)
+	CancelCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error)
+}
+
+// Campaign sends the same content, or the same template, to a list of
+// recipients. Each recipient gets its own message linked by campaign_id.
+type Campaign struct {
+	ID                primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Name              string              `bson:"name" json:"name" validate:"required,max=100"`
+	Content           string              `bson:"content,omitempty" json:"content,omitempty" validate:"required_without=TemplateID,segments"`
+	TemplateID        *primitive.ObjectID `bson:"template_id,omitempty" json:"template_id,omitempty"`
+	TemplateVariables map[string]string   `bson:"template_variables,omitempty" json:"template_variables,omitempty"`
+	Status            string              `bson:"status" json:"status"`
+	Total             int                 `bson:"total" json:"total"` // number of messages enqueued for the campaign
+	CreatedAt         time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// CampaignProgress is a campaign with the number of its messages per status.
+type CampaignProgress struct {
+	Campaign
+	Counts map[string]int64 `json:"counts"`
+}
+
+type CreateCampaignRequest struct {
+	Name           string            `json:"name"`
+	Content        string            `json:"content,omitempty"`
+	TemplateID     string            `json:"template_id,omitempty"` // used instead of content, rendered at dispatch time
+	Variables      map[string]string `json:"variables,omitempty"`   // values for the template placeholders, shared by all recipients
+	Recipients     []string          `json:"recipients"`            // E.164 phone numbers, duplicates are ignored
+	Priority       string            `json:"priority,omitempty"`    // high, normal or low; defaults to normal
+	ScheduledAt    *time.Time        `json:"scheduled_at,omitempty"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`      // the messages are not delivered after this time
+	DeliveryWindow *DeliveryWindow   `json:"delivery_window,omitempty"` // local hours the messages may be delivered in
+}
+
+type CreateCampaignResponse struct {
+	ID string `json:"id"`
+}
+
+type CampaignActionRequest struct {
+	Action string `json:"action"` // "pause", "resume" or "cancel"
+}
+
+type CampaignHandler struct {
+	campaignService CampaignService
+}
+
+func NewCampaignHandler(cs CampaignService) *CampaignHandler {
+	return &CampaignHandler{
+		campaignService: cs,
+	}
+}
+
+func (h *CampaignHandler) RegisterRoutes(app *fiber.App) {
+	campaignGroup := app.Group("/campaigns")
+	campaignGroup.Post("/", h.CreateCampaign)
+	campaignGroup.Get("/:id", h.RetrieveCampaign)
+	campaignGroup.Put("/:id/state", h.ControlCampaign)
+}
+
+// CreateCampaign godoc
+// @Summary Create a campaign
+// @Description Enqueue one message per recipient with the given content or template
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param campaign body CreateCampaignRequest true "Campaign to create"
+// @Success 201 {object} CreateCampaignResponse
+// @Failure 400 {object} map[string]string "Invalid request body or validation error"
+// @Failure 500 {object} map[string]string "Internal server error, with the ID of the cancelled campaign if its messages could not all be enqueued"
+// @Router /campaigns [post]
+func (h *CampaignHandler) CreateCampaign(c *fiber.Ctx) error {
+	var req CreateCampaignRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	id, err := h.campaignService.CreateCampaign(c.UserContext(), req)
+	if errors.Is(err, ErrCampaignIncomplete) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"id":    id.Hex(),
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CreateCampaignResponse{
+		ID: id.Hex(),
+	})
+}
+
+// RetrieveCampaign godoc
+// @Summary Retrieve a campaign
+// @Description Get a campaign with the number of its messages per status
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} CampaignProgress
+// @Failure 400 {object} map[string]string "Invalid campaign ID"
+// @Failure 404 {object} nil "Campaign not found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /campaigns/{id} [get]
+func (h *CampaignHandler) RetrieveCampaign(c *fiber.Ctx) error {
+	campaignID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidCampaignID.Error(),
+		})
+	}
+
+	progress, err := h.campaignService.RetrieveCampaign(c.UserContext(), campaignID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(progress)
+}
+
+// ControlCampaign godoc
+// @Summary Updates a campaign state
+// @Description Pause, resume or cancel the unsent messages of a campaign
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param action body CampaignActionRequest true "Action to perform `pause`, `resume` or `cancel`"
+// @Success 200 {object} Campaign
+// @Failure 400 {object} map[string]string "Invalid ID or action"
+// @Failure 404 {object} nil "Campaign not found"
+// @Failure 409 {object} map[string]string "Action not allowed in the campaign's current state"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /campaigns/{id}/state [put]
+func (h *CampaignHandler) ControlCampaign(c *fiber.Ctx) error {
+	campaignID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidCampaignID.Error(),
+		})
+	}
+
+	var req CampaignActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	var campaign *Campaign
+	switch req.Action {
+	case "pause":
+		campaign, err = h.campaignService.PauseCampaign(c.UserContext(), campaignID)
+	case "resume":
+		campaign, err = h.campaignService.ResumeCampaign(c.UserContext(), campaignID)
+	case "cancel":
+		campaign, err = h.campaignService.CancelCampaign(c.UserContext(), campaignID)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid action. Use 'pause', 'resume' or 'cancel'",
+		})
+	}
+
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(campaign)
+}
+
+func (h *CampaignHandler) errorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrValidationFailed):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, ErrDocumentNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, ErrCampaignStateConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestCampaignHandler_CreateCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockCampaignService(ctrl)
+	handler := NewCampaignHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	createdID := primitive.NewObjectID()
+	validRequest := CreateCampaignRequest{
+		Name:       "spring-sale",
+		Content:    "Spring sale starts today!",
+		Recipients: []string{"+15553579024", "+15553579025"},
+	}
+
+	tests := []struct {
+		name        string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should create campaign with status 201",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusCreated,
+			wantBody:    fmt.Sprintf(`{"id":"%s"}`, createdID.Hex()),
+			beforeSuite: func() {
+				mockService.EXPECT().CreateCampaign(gomock.Any(), validRequest).Return(createdID, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when validation fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"validation failed: recipients are required"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateCampaign(gomock.Any(), validRequest).Return(primitive.NilObjectID, fmt.Errorf("%w: recipients are required", ErrValidationFailed))
+			},
+		},
+		{
+			name:        "should return error with status 500 when service fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateCampaign(gomock.Any(), validRequest).Return(primitive.NilObjectID, ErrInternalServerError)
+			},
+		},
+		{
+			name:        "should return campaign ID with status 500 when messages cannot all be enqueued",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusInternalServerError,
+			wantBody:    fmt.Sprintf(`{"id":"%s","error":"%s"}`, createdID.Hex(), ErrCampaignIncomplete.Error()),
+			beforeSuite: func() {
+				mockService.EXPECT().CreateCampaign(gomock.Any(), validRequest).Return(createdID, ErrCampaignIncomplete)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, "/campaigns", reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestCampaignHandler_RetrieveCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockCampaignService(ctrl)
+	handler := NewCampaignHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	campaignID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should return campaign progress with status 200",
+			url:        "/campaigns/" + campaignID.Hex(),
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveCampaign(gomock.Any(), campaignID).Return(&CampaignProgress{
+					Campaign: Campaign{ID: campaignID, Name: "spring-sale", Status: CampaignStatusActive, Total: 2},
+					Counts:   map[string]int64{StatusUnsent: 1, StatusProcessing: 0, StatusSent: 1, StatusFailed: 0},
+				}, nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when campaign is not found",
+			url:        "/campaigns/" + campaignID.Hex(),
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveCampaign(gomock.Any(), campaignID).Return(nil, ErrDocumentNotFound)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/campaigns/not-an-id",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestCampaignHandler_ControlCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockCampaignService(ctrl)
+	handler := NewCampaignHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	campaignID := primitive.NewObjectID()
+	campaignPath := "/campaigns/" + campaignID.Hex() + "/state"
+
+	tests := []struct {
+		name        string
+		action      string
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should pause campaign",
+			action:     "pause",
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().PauseCampaign(gomock.Any(), campaignID).Return(&Campaign{ID: campaignID, Status: CampaignStatusPaused}, nil)
+			},
+		},
+		{
+			name:       "should resume campaign",
+			action:     "resume",
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().ResumeCampaign(gomock.Any(), campaignID).Return(&Campaign{ID: campaignID, Status: CampaignStatusActive}, nil)
+			},
+		},
+		{
+			name:       "should cancel campaign",
+			action:     "cancel",
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().CancelCampaign(gomock.Any(), campaignID).Return(&Campaign{ID: campaignID, Status: CampaignStatusCancelled}, nil)
+			},
+		},
+		{
+			name:       "should return error with status 409 when state does not allow the action",
+			action:     "resume",
+			wantStatus: fiber.StatusConflict,
+			beforeSuite: func() {
+				mockService.EXPECT().ResumeCampaign(gomock.Any(), campaignID).Return(nil, ErrCampaignStateConflict)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid action",
+			action:      "archive",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			jsonBody, err := json.Marshal(CampaignActionRequest{Action: tt.action})
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(fiber.MethodPut, campaignPath, bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
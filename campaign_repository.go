@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CampaignStatusActive    = "active"
+	CampaignStatusPaused    = "paused"
+	CampaignStatusCancelled = "cancelled"
+)
+
+var ErrCampaignStateConflict = errors.New("campaign is not in a state that allows this action")
+
+type CampaignRepositoryImpl struct {
+	campaignCollection *mongo.Collection
+}
+
+func NewCampaignRepositoryImpl(collection *mongo.Collection) *CampaignRepositoryImpl {
+	return &CampaignRepositoryImpl{
+		campaignCollection: collection,
+	}
+}
+
+func (cr *CampaignRepositoryImpl) InsertCampaign(ctx context.Context, campaign *Campaign) (primitive.ObjectID, error) {
+	result, err := cr.campaignCollection.InsertOne(ctx, campaign)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, ErrInvalidMessageID
+	}
+
+	return id, nil
+}
+
+func (cr *CampaignRepositoryImpl) RetrieveCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error) {
+	var campaign Campaign
+	err := cr.campaignCollection.FindOne(ctx, bson.M{"_id": campaignID}).Decode(&campaign)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// UpdateCampaignStatus moves the campaign to status if it is currently in one
+// of the from statuses, and returns the updated campaign. A campaign in any
+// other status is left untouched and ErrCampaignStateConflict is returned.
+func (cr *CampaignRepositoryImpl) UpdateCampaignStatus(ctx context.Context, campaignID primitive.ObjectID, from []string, status string) (*Campaign, error) {
+	filter := bson.M{
+		"_id":    campaignID,
+		"status": bson.M{"$in": from},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"updated_at": time.Now(),
+		},
+	}
+
+	var campaign Campaign
+	err := cr.campaignCollection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&campaign)
+	if err == nil {
+		return &campaign, nil
+	}
+
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	count, err := cr.campaignCollection.CountDocuments(ctx, bson.M{"_id": campaignID})
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	return nil, ErrCampaignStateConflict
+}
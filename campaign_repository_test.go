@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const testCampaignCollection = "campaigns"
+
+func TestCampaignRepository_UpdateCampaignStatus(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	campaignRepository := NewCampaignRepositoryImpl(client.Database(testDB).Collection(testCampaignCollection))
+
+	id, err := campaignRepository.InsertCampaign(context.Background(), &Campaign{
+		ID:      primitive.NewObjectID(),
+		Name:    "spring-sale",
+		Content: "Spring sale starts today!",
+		Status:  CampaignStatusActive,
+		Total:   2,
+	})
+	assert.NoError(t, err)
+
+	paused, err := campaignRepository.UpdateCampaignStatus(context.Background(), id, []string{CampaignStatusActive}, CampaignStatusPaused)
+	assert.NoError(t, err)
+	assert.Equal(t, CampaignStatusPaused, paused.Status)
+	assert.False(t, paused.UpdatedAt.IsZero())
+
+	_, err = campaignRepository.UpdateCampaignStatus(context.Background(), id, []string{CampaignStatusActive}, CampaignStatusPaused)
+	assert.Equal(t, ErrCampaignStateConflict, err)
+
+	_, err = campaignRepository.UpdateCampaignStatus(context.Background(), primitive.NewObjectID(), []string{CampaignStatusActive}, CampaignStatusPaused)
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	campaign, err := campaignRepository.RetrieveCampaign(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, CampaignStatusPaused, campaign.Status)
+	assert.Equal(t, 2, campaign.Total)
+
+	_, err = campaignRepository.RetrieveCampaign(context.Background(), primitive.NewObjectID())
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxCampaignRecipients = 10000
+
+var (
+	ErrInvalidCampaignID  = errors.New("invalid campaign ID")
+	ErrCampaignIncomplete = errors.New("campaign messages could not all be enqueued, the campaign was cancelled")
+)
+
+type CampaignRepository interface {
+	InsertCampaign(ctx context.Context, campaign *Campaign) (primitive.ObjectID, error)
+	RetrieveCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error)
+	UpdateCampaignStatus(ctx context.Context, campaignID primitive.ObjectID, from []string, status string) (*Campaign, error)
+}
+
+type CampaignMessageStore interface {
+	InsertMessages(ctx context.Context, messages []Message) (map[int]string, error)
+	SetCampaignHold(ctx context.Context, campaignID primitive.ObjectID, held bool) (int64, error)
+	CancelCampaignMessages(ctx context.Context, campaignID primitive.ObjectID) (int64, error)
+	CountCampaignMessages(ctx context.Context, campaignID primitive.ObjectID) (map[string]int64, error)
+}
+
+type CampaignServiceImpl struct {
+	campaignRepository CampaignRepository
+	messageStore       CampaignMessageStore
+	config             Config
+	validate           *validator.Validate
+}
+
+func NewCampaignServiceImpl(cr CampaignRepository, ms CampaignMessageStore, cfg Config, validate *validator.Validate) *CampaignServiceImpl {
+	return &CampaignServiceImpl{
+		campaignRepository: cr,
+		messageStore:       ms,
+		config:             cfg,
+		validate:           validate,
+	}
+}
+
+// CreateCampaign stores the campaign and enqueues one message per distinct
+// recipient, compared after phone number normalization. Every message is
+// validated before anything is written, so a bad recipient rejects the whole
+// campaign. If the campaign is stored but its messages cannot all be enqueued,
+// the campaign is cancelled and its ID is returned with ErrCampaignIncomplete.
+func (cs *CampaignServiceImpl) CreateCampaign(ctx context.Context, req CreateCampaignRequest) (primitive.ObjectID, error) {
+	templateID, err := parseMessageTemplateID(req.Content, req.TemplateID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	recipients := distinctRecipients(req.Recipients)
+	if len(recipients) == 0 {
+		return primitive.NilObjectID, fmt.Errorf("%w: recipients are required", ErrValidationFailed)
+	}
+	if len(recipients) > maxCampaignRecipients {
+		return primitive.NilObjectID, fmt.Errorf("%w: a campaign can have at most %d recipients", ErrValidationFailed, maxCampaignRecipients)
+	}
+
+	now := time.Now()
+	campaign := &Campaign{
+		ID:                primitive.NewObjectID(),
+		Name:              req.Name,
+		Content:           req.Content,
+		TemplateID:        templateID,
+		TemplateVariables: req.Variables,
+		Status:            CampaignStatusActive,
+		Total:             len(recipients),
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	if err := cs.validate.Struct(campaign); err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+
+	messages := make([]Message, 0, len(recipients))
+	normalized := make(map[string]struct{}, len(recipients))
+	for i, recipient := range recipients {
+		message := newUnsentMessage(req.Content, recipient, req.Priority, req.ScheduledAt, now)
+		message.CampaignID = &campaign.ID
+		message.ExpiresAt = req.ExpiresAt
+		message.DeliveryWindow = req.DeliveryWindow
+		message.TemplateID = templateID
+		message.TemplateVariables = req.Variables
+
+		if err := cs.validate.Struct(message); err != nil {
+			return primitive.NilObjectID, fmt.Errorf("%w: recipients[%d]: %s", ErrValidationFailed, i, err.Error())
+		}
+		if err := normalizePhoneNumberField(&message.RecipientPhoneNumber, cs.config.Phone.DefaultRegion); err != nil {
+			return primitive.NilObjectID, fmt.Errorf("recipients[%d]: %w", i, err)
+		}
+
+		if _, ok := normalized[message.RecipientPhoneNumber]; ok {
+			continue
+		}
+		normalized[message.RecipientPhoneNumber] = struct{}{}
+		messages = append(messages, *message)
+	}
+	campaign.Total = len(messages)
+
+	id, err := cs.campaignRepository.InsertCampaign(ctx, campaign)
+	if err != nil {
+		return primitive.NilObjectID, ErrInternalServerError
+	}
+
+	if err := cs.insertMessages(ctx, messages); err != nil {
+		// Do not leave a half enqueued campaign running.
+		_, _ = cs.CancelCampaign(ctx, id)
+		return id, ErrCampaignIncomplete
+	}
+
+	return id, nil
+}
+
+func (cs *CampaignServiceImpl) insertMessages(ctx context.Context, messages []Message) error {
+	batchSize := cs.config.BulkImport.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(messages)
+	}
+
+	for start := 0; start < len(messages); start += batchSize {
+		end := min(start+batchSize, len(messages))
+
+		failed, err := cs.messageStore.InsertMessages(ctx, messages[start:end])
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%d campaign messages were not inserted", len(failed))
+		}
+	}
+
+	return nil
+}
+
+func distinctRecipients(recipients []string) []string {
+	seen := make(map[string]struct{}, len(recipients))
+	distinct := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		if _, ok := seen[recipient]; ok {
+			continue
+		}
+		seen[recipient] = struct{}{}
+		distinct = append(distinct, recipient)
+	}
+
+	return distinct
+}
+
+// RetrieveCampaign returns the campaign with the number of its messages per
+// status. unsent, processing, sent and failed are always present.
+func (cs *CampaignServiceImpl) RetrieveCampaign(ctx context.Context, campaignID primitive.ObjectID) (*CampaignProgress, error) {
+	campaign, err := cs.campaignRepository.RetrieveCampaign(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, ErrInternalServerError
+	}
+
+	counts, err := cs.messageStore.CountCampaignMessages(ctx, campaignID)
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	for _, status := range []string{StatusUnsent, StatusProcessing, StatusSent, StatusFailed} {
+		if _, ok := counts[status]; !ok {
+			counts[status] = 0
+		}
+	}
+
+	return &CampaignProgress{
+		Campaign: *campaign,
+		Counts:   counts,
+	}, nil
+}
+
+// PauseCampaign holds the campaign's messages so workers stop claiming them.
+// Messages already claimed finish their current attempt.
+func (cs *CampaignServiceImpl) PauseCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error) {
+	campaign, err := cs.campaignRepository.UpdateCampaignStatus(ctx, campaignID, []string{CampaignStatusActive}, CampaignStatusPaused)
+	if err != nil {
+		return nil, mapCampaignError(err)
+	}
+
+	if _, err := cs.messageStore.SetCampaignHold(ctx, campaignID, true); err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	return campaign, nil
+}
+
+func (cs *CampaignServiceImpl) ResumeCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error) {
+	campaign, err := cs.campaignRepository.UpdateCampaignStatus(ctx, campaignID, []string{CampaignStatusPaused}, CampaignStatusActive)
+	if err != nil {
+		return nil, mapCampaignError(err)
+	}
+
+	if _, err := cs.messageStore.SetCampaignHold(ctx, campaignID, false); err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	return campaign, nil
+}
+
+// CancelCampaign cancels every unsent message of the campaign. A message that is
+// in flight finishes its current attempt and is cancelled instead of being
+// retried or deferred.
+func (cs *CampaignServiceImpl) CancelCampaign(ctx context.Context, campaignID primitive.ObjectID) (*Campaign, error) {
+	campaign, err := cs.campaignRepository.UpdateCampaignStatus(ctx, campaignID, []string{CampaignStatusActive, CampaignStatusPaused}, CampaignStatusCancelled)
+	if err != nil {
+		return nil, mapCampaignError(err)
+	}
+
+	if _, err := cs.messageStore.SetCampaignHold(ctx, campaignID, true); err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if _, err := cs.messageStore.CancelCampaignMessages(ctx, campaignID); err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	return campaign, nil
+}
+
+func mapCampaignError(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return ErrDocumentNotFound
+	case errors.Is(err, ErrCampaignStateConflict):
+		return ErrCampaignStateConflict
+	default:
+		return ErrInternalServerError
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestCampaignService_CreateCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCampaignRepo := NewMockCampaignRepository(ctrl)
+	mockMessageStore := NewMockCampaignMessageStore(ctrl)
+	service := NewCampaignServiceImpl(mockCampaignRepo, mockMessageStore, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, BulkImport: BulkImportConfig{BatchSize: 2}}, newTestValidator())
+
+	createdID := primitive.NewObjectID()
+	templateID := primitive.NewObjectID()
+	validRequest := CreateCampaignRequest{
+		Name:       "spring-sale",
+		Content:    "Spring sale starts today!",
+		Recipients: []string{"+15553579024", "+15553579025", "+1 (555) 357-9024", "+15553579026"},
+	}
+
+	tests := []struct {
+		name        string
+		req         CreateCampaignRequest
+		wantID      primitive.ObjectID
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should create campaign with one message per distinct recipient",
+			req:     validRequest,
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				var campaignID primitive.ObjectID
+				mockCampaignRepo.EXPECT().InsertCampaign(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, campaign *Campaign) (primitive.ObjectID, error) {
+					assert.Equal(t, CampaignStatusActive, campaign.Status)
+					assert.Equal(t, 3, campaign.Total)
+					campaignID = campaign.ID
+					return createdID, nil
+				})
+				mockMessageStore.EXPECT().InsertMessages(gomock.Any(), gomock.Len(2)).DoAndReturn(func(_ context.Context, messages []Message) (map[int]string, error) {
+					assert.Equal(t, "+15553579024", messages[0].RecipientPhoneNumber)
+					assert.Equal(t, "+15553579025", messages[1].RecipientPhoneNumber)
+					assert.Equal(t, &campaignID, messages[0].CampaignID)
+					assert.Equal(t, StatusUnsent, messages[0].Status)
+					return nil, nil
+				})
+				mockMessageStore.EXPECT().InsertMessages(gomock.Any(), gomock.Len(1)).Return(nil, nil)
+			},
+		},
+		{
+			name: "should create template campaign",
+			req: CreateCampaignRequest{
+				Name:       "otp",
+				TemplateID: templateID.Hex(),
+				Variables:  map[string]string{"store": "Downtown"},
+				Recipients: []string{"+15553579024"},
+			},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().InsertCampaign(gomock.Any(), gomock.Any()).Return(createdID, nil)
+				mockMessageStore.EXPECT().InsertMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, messages []Message) (map[int]string, error) {
+					assert.Equal(t, &templateID, messages[0].TemplateID)
+					assert.Equal(t, map[string]string{"store": "Downtown"}, messages[0].TemplateVariables)
+					return nil, nil
+				})
+			},
+		},
+		{
+			name:        "should return validation error when recipients are empty",
+			req:         CreateCampaignRequest{Name: "spring-sale", Content: "Spring sale starts today!"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when a recipient is invalid",
+			req:         CreateCampaignRequest{Name: "spring-sale", Content: "Spring sale starts today!", Recipients: []string{"+15553579024", "not-a-number"}},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when name is missing",
+			req:         CreateCampaignRequest{Content: "Spring sale starts today!", Recipients: []string{"+15553579024"}},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should cancel campaign and return its ID when messages cannot be inserted",
+			req:     validRequest,
+			wantID:  createdID,
+			wantErr: ErrCampaignIncomplete,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().InsertCampaign(gomock.Any(), gomock.Any()).Return(createdID, nil)
+				mockMessageStore.EXPECT().InsertMessages(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), createdID, gomock.Any(), CampaignStatusCancelled).Return(&Campaign{ID: createdID}, nil)
+				mockMessageStore.EXPECT().SetCampaignHold(gomock.Any(), createdID, true).Return(int64(0), nil)
+				mockMessageStore.EXPECT().CancelCampaignMessages(gomock.Any(), createdID).Return(int64(0), nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.CreateCampaign(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantID, got)
+		})
+	}
+}
+
+func TestCampaignService_RetrieveCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCampaignRepo := NewMockCampaignRepository(ctrl)
+	mockMessageStore := NewMockCampaignMessageStore(ctrl)
+	service := NewCampaignServiceImpl(mockCampaignRepo, mockMessageStore, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	campaignID := primitive.NewObjectID()
+	campaign := &Campaign{ID: campaignID, Name: "spring-sale", Status: CampaignStatusActive, Total: 5, CreatedAt: time.Now()}
+
+	tests := []struct {
+		name        string
+		want        *CampaignProgress
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name: "should return campaign with counts per status",
+			want: &CampaignProgress{
+				Campaign: *campaign,
+				Counts:   map[string]int64{StatusUnsent: 0, StatusProcessing: 1, StatusSent: 3, StatusFailed: 0, StatusCancelled: 1},
+			},
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().RetrieveCampaign(gomock.Any(), campaignID).Return(campaign, nil)
+				mockMessageStore.EXPECT().CountCampaignMessages(gomock.Any(), campaignID).Return(map[string]int64{StatusProcessing: 1, StatusSent: 3, StatusCancelled: 1}, nil)
+			},
+		},
+		{
+			name:    "should return not found when campaign is missing",
+			want:    nil,
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().RetrieveCampaign(gomock.Any(), campaignID).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return internal error when counting fails",
+			want:    nil,
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().RetrieveCampaign(gomock.Any(), campaignID).Return(campaign, nil)
+				mockMessageStore.EXPECT().CountCampaignMessages(gomock.Any(), campaignID).Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.RetrieveCampaign(context.Background(), campaignID)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCampaignService_ControlCampaign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCampaignRepo := NewMockCampaignRepository(ctrl)
+	mockMessageStore := NewMockCampaignMessageStore(ctrl)
+	service := NewCampaignServiceImpl(mockCampaignRepo, mockMessageStore, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	campaignID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		action      func() (*Campaign, error)
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should pause campaign and hold its messages",
+			action:  func() (*Campaign, error) { return service.PauseCampaign(context.Background(), campaignID) },
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), campaignID, []string{CampaignStatusActive}, CampaignStatusPaused).Return(&Campaign{ID: campaignID, Status: CampaignStatusPaused}, nil)
+				mockMessageStore.EXPECT().SetCampaignHold(gomock.Any(), campaignID, true).Return(int64(3), nil)
+			},
+		},
+		{
+			name:    "should resume campaign and release its messages",
+			action:  func() (*Campaign, error) { return service.ResumeCampaign(context.Background(), campaignID) },
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), campaignID, []string{CampaignStatusPaused}, CampaignStatusActive).Return(&Campaign{ID: campaignID, Status: CampaignStatusActive}, nil)
+				mockMessageStore.EXPECT().SetCampaignHold(gomock.Any(), campaignID, false).Return(int64(3), nil)
+			},
+		},
+		{
+			name:    "should cancel campaign and its unsent messages",
+			action:  func() (*Campaign, error) { return service.CancelCampaign(context.Background(), campaignID) },
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), campaignID, []string{CampaignStatusActive, CampaignStatusPaused}, CampaignStatusCancelled).Return(&Campaign{ID: campaignID, Status: CampaignStatusCancelled}, nil)
+				mockMessageStore.EXPECT().SetCampaignHold(gomock.Any(), campaignID, true).Return(int64(3), nil)
+				mockMessageStore.EXPECT().CancelCampaignMessages(gomock.Any(), campaignID).Return(int64(2), nil)
+			},
+		},
+		{
+			name:    "should return conflict when resuming a cancelled campaign",
+			action:  func() (*Campaign, error) { return service.ResumeCampaign(context.Background(), campaignID) },
+			wantErr: ErrCampaignStateConflict,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), campaignID, gomock.Any(), CampaignStatusActive).Return(nil, ErrCampaignStateConflict)
+			},
+		},
+		{
+			name:    "should return not found when campaign is missing",
+			action:  func() (*Campaign, error) { return service.PauseCampaign(context.Background(), campaignID) },
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), campaignID, gomock.Any(), CampaignStatusPaused).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return internal error when holding messages fails",
+			action:  func() (*Campaign, error) { return service.PauseCampaign(context.Background(), campaignID) },
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockCampaignRepo.EXPECT().UpdateCampaignStatus(gomock.Any(), campaignID, gomock.Any(), CampaignStatusPaused).Return(&Campaign{ID: campaignID}, nil)
+				mockMessageStore.EXPECT().SetCampaignHold(gomock.Any(), campaignID, true).Return(int64(0), assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			_, err := tt.action()
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
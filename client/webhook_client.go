@@ -4,15 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 )
 
+const maxErrorBodySnippet = 512
+
 type WebhookClientConfig struct {
 	Timeout time.Duration `json:"timeout"`
 	Path    string        `json:"path"`
 	Host    string        `json:"host"`
+	// ForwardMetadata sends the message's external ID, tags and metadata along
+	// with the recipient and content.
+	ForwardMetadata bool `json:"forwardMetadata"`
 }
 
 type WebhookResponse struct {
@@ -21,8 +30,66 @@ type WebhookResponse struct {
 }
 
 type WebhookRequest struct {
-	To      string `json:"to"`
-	Content string `json:"content"`
+	To         string            `json:"to"`
+	Content    string            `json:"content"`
+	ExternalID string            `json:"externalId,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+}
+
+// WebhookError describes a failed webhook call. Retryable tells the caller
+// whether repeating the same request may succeed (timeouts, 429, 5xx) or not
+// (e.g. 400 for a payload the webhook will never accept). NotSent is set when
+// the request provably never reached the webhook, e.g. the connection could
+// not be established.
+type WebhookError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+	Retryable  bool
+	NotSent    bool
+	Err        error
+}
+
+func (e *WebhookError) Error() string {
+	switch {
+	case e.StatusCode == 0:
+		return fmt.Sprintf("failed to post message: %v", e.Err)
+	case e.Err != nil:
+		return fmt.Sprintf("failed to read webhook response, status code: %d: %v", e.StatusCode, e.Err)
+	default:
+		return fmt.Sprintf("failed to post message, status code: %d", e.StatusCode)
+	}
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// IsUndelivered reports whether err proves the webhook did not accept the
+// message: the request never left, or the webhook answered with a non-2xx
+// status. Any other failure, e.g. a timeout after the request was written, is
+// ambiguous because the webhook may have accepted the message.
+func IsUndelivered(err error) bool {
+	var webhookErr *WebhookError
+	if !errors.As(err, &webhookErr) {
+		return false
+	}
+
+	if webhookErr.NotSent {
+		return true
+	}
+
+	return webhookErr.StatusCode != 0 && (webhookErr.StatusCode < 200 || webhookErr.StatusCode > 299)
+}
+
+// IsRetryable reports whether err is a webhook error worth retrying.
+func IsRetryable(err error) bool {
+	var webhookErr *WebhookError
+	if errors.As(err, &webhookErr) {
+		return webhookErr.Retryable
+	}
+	return false
 }
 
 type WebhookClient struct {
@@ -40,14 +107,19 @@ func NewWebhookClient(baseURL string, httpClient *http.Client, config *WebhookCl
 }
 
 func (c *WebhookClient) PostMessage(ctx context.Context, message *WebhookRequest) (*WebhookResponse, error) {
-	body, err := json.Marshal(message)
+	payload := *message
+	if !c.config.ForwardMetadata {
+		payload.ExternalID, payload.Tags, payload.Metadata = "", nil, nil
+	}
+
+	body, err := json.Marshal(payload)
 	if err != nil {
-		return nil, err
+		return nil, &WebhookError{NotSent: true, Err: err}
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+c.config.Path, bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		return nil, &WebhookError{NotSent: true, Err: err}
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -55,19 +127,60 @@ func (c *WebhookClient) PostMessage(ctx context.Context, message *WebhookRequest
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, &WebhookError{Retryable: true, NotSent: isDialError(err), Err: err}
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusAccepted {
-		return nil, fmt.Errorf("failed to post message, status code: %d", resp.StatusCode)
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySnippet))
+		return nil, &WebhookError{
+			StatusCode: resp.StatusCode,
+			Body:       string(snippet),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Retryable:  isRetryableStatus(resp.StatusCode),
+		}
 	}
 
 	var webhookResponse WebhookResponse
 	if err := json.NewDecoder(resp.Body).Decode(&webhookResponse); err != nil {
-		return nil, err
+		return nil, &WebhookError{StatusCode: resp.StatusCode, Err: err}
 	}
 
 	return &webhookResponse, nil
 }
+
+// isDialError reports whether the request failed while connecting, before any
+// of it was written.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter accepts both forms of the Retry-After header: delay seconds
+// and an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if retryAt, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(retryAt); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
@@ -3,11 +3,13 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -46,7 +48,7 @@ func TestClient_PostMessage(t *testing.T) {
 			name:    "failed message post",
 			message: &WebhookRequest{To: "+1234567890", Content: "Test message"},
 			want:    nil,
-			wantErr: fmt.Errorf("failed to post message, status code: %d", http.StatusInternalServerError),
+			wantErr: &WebhookError{StatusCode: http.StatusInternalServerError, Retryable: true},
 			beforeSuite: func() *httptest.Server {
 				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					assert.True(t, regexp.MustCompile("^/.*$").MatchString(r.URL.Path))
@@ -61,6 +63,34 @@ func TestClient_PostMessage(t *testing.T) {
 					w.WriteHeader(http.StatusInternalServerError)
 				}))
 
+				return server
+			},
+		},
+		{
+			name:    "bad request is not retryable",
+			message: &WebhookRequest{To: "+1234567890", Content: "Test message"},
+			want:    nil,
+			wantErr: &WebhookError{StatusCode: http.StatusBadRequest, Body: `{"error":"invalid recipient"}`, Retryable: false},
+			beforeSuite: func() *httptest.Server {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"error":"invalid recipient"}`)
+				}))
+
+				return server
+			},
+		},
+		{
+			name:    "too many requests is retryable with retry after",
+			message: &WebhookRequest{To: "+1234567890", Content: "Test message"},
+			want:    nil,
+			wantErr: &WebhookError{StatusCode: http.StatusTooManyRequests, RetryAfter: 120 * time.Second, Retryable: true},
+			beforeSuite: func() *httptest.Server {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Retry-After", "120")
+					w.WriteHeader(http.StatusTooManyRequests)
+				}))
+
 				return server
 			},
 		},
@@ -78,3 +108,101 @@ func TestClient_PostMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_PostMessageForwardMetadata(t *testing.T) {
+	message := &WebhookRequest{
+		To:         "+1234567890",
+		Content:    "Test message",
+		ExternalID: "order-42",
+		Tags:       []string{"checkout"},
+		Metadata:   map[string]string{"store": "istanbul"},
+	}
+
+	tests := []struct {
+		name            string
+		forwardMetadata bool
+		want            WebhookRequest
+	}{
+		{
+			name:            "metadata is forwarded when enabled",
+			forwardMetadata: true,
+			want:            *message,
+		},
+		{
+			name:            "metadata is left out by default",
+			forwardMetadata: false,
+			want:            WebhookRequest{To: "+1234567890", Content: "Test message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req WebhookRequest
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				assert.Equal(t, tt.want, req)
+
+				w.WriteHeader(http.StatusAccepted)
+				json.NewEncoder(w).Encode(WebhookResponse{Message: "Accepted", MessageID: "webhook-message-id"})
+			}))
+			defer server.Close()
+
+			client := NewWebhookClient(server.URL, &http.Client{}, &WebhookClientConfig{Path: "/", ForwardMetadata: tt.forwardMetadata})
+			_, err := client.PostMessage(context.Background(), message)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_PostMessageTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := NewWebhookClient(server.URL, &http.Client{}, &WebhookClientConfig{Path: "/a4d12c37-21b5-4470-92ad-357329f2b48c"})
+	got, err := client.PostMessage(context.Background(), &WebhookRequest{To: "+1234567890", Content: "Test message"})
+	assert.Nil(t, got)
+	assert.True(t, IsRetryable(err))
+
+	var webhookErr *WebhookError
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, 0, webhookErr.StatusCode)
+	assert.True(t, webhookErr.NotSent)
+	assert.True(t, IsUndelivered(err))
+}
+
+func TestClient_PostMessageTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewWebhookClient(server.URL, &http.Client{Timeout: 50 * time.Millisecond}, &WebhookClientConfig{Path: "/a4d12c37-21b5-4470-92ad-357329f2b48c"})
+	got, err := client.PostMessage(context.Background(), &WebhookRequest{To: "+1234567890", Content: "Test message"})
+	assert.Nil(t, got)
+	assert.True(t, IsRetryable(err))
+
+	// The request reached the server, which may have accepted it.
+	assert.False(t, IsUndelivered(err))
+}
+
+func TestIsUndelivered(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server error answer", err: &WebhookError{StatusCode: http.StatusServiceUnavailable, Retryable: true}, want: true},
+		{name: "client error answer", err: &WebhookError{StatusCode: http.StatusBadRequest}, want: true},
+		{name: "request never sent", err: &WebhookError{Retryable: true, NotSent: true, Err: errors.New("connection refused")}, want: true},
+		{name: "timeout after the request was written", err: &WebhookError{Retryable: true, Err: context.DeadlineExceeded}, want: false},
+		{name: "unreadable accepted response", err: &WebhookError{StatusCode: http.StatusAccepted, Err: errors.New("unexpected EOF")}, want: false},
+		{name: "other error", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsUndelivered(tt.err))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/desxz/go-message-scheduler/client"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
@@ -12,6 +13,13 @@ type Config struct {
 	Pool          PoolConfig
 	RateLimiter   RateLimiterConfig
 	MongoDB       MongoDBConfig
+	Recurring     RecurringSchedulerConfig
+	Idempotency   IdempotencyConfig
+	BulkImport    BulkImportConfig
+	Phone         PhoneConfig
+	SMS           SMSConfig
+	ContentFilter ContentFilterConfig
+	Stats         StatsConfig
 }
 
 func NewConfig(configPath, configEnv string) (*Config, error) {
@@ -29,6 +37,28 @@ func NewConfig(configPath, configEnv string) (*Config, error) {
 	return config, nil
 }
 
+// WatchConfig calls onChange with the reloaded config every time the config
+// file is written. Settings that are only read at startup are not affected; it
+// is up to onChange to apply the parts that can change at runtime.
+func WatchConfig(configPath, configEnv string, onChange func(*Config, error)) error {
+	viperConfig, err := readConfig(configPath, configEnv)
+	if err != nil {
+		return err
+	}
+
+	viperConfig.OnConfigChange(func(fsnotify.Event) {
+		config := new(Config)
+		if err := viperConfig.Unmarshal(config); err != nil {
+			onChange(nil, err)
+			return
+		}
+		onChange(config, nil)
+	})
+	viperConfig.WatchConfig()
+
+	return nil
+}
+
 func readConfig(configPath, configName string) (*viper.Viper, error) {
 	v := viper.New()
 	v.AddConfigPath(configPath)
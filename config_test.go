@@ -23,6 +23,15 @@ func TestConfig_New(t *testing.T) {
 			want: &Config{
 				Worker: WorkerConfig{
 					WorkerJobInterval: 5 * time.Second,
+					Retry: RetryPolicy{
+						MaxAttempts: 5,
+						BaseDelay:   30 * time.Second,
+						Multiplier:  2,
+						Jitter:      0.2,
+					},
+					SendGuardTTL:     24 * time.Hour,
+					LowPriorityShare: 0.1,
+					DedupeWindow:     10 * time.Minute,
 				},
 				WebhookClient: client.WebhookClientConfig{
 					Timeout: 30 * time.Second,
@@ -33,9 +42,12 @@ func TestConfig_New(t *testing.T) {
 					TTL: 24 * time.Hour,
 				},
 				Pool: PoolConfig{
-					NumWorkers:      2,
-					Timeout:         10 * time.Second,
-					InitialJobFetch: true,
+					NumWorkers:          2,
+					Timeout:             10 * time.Second,
+					InitialJobFetch:     true,
+					LeaseDuration:       5 * time.Minute,
+					ReaperInterval:      time.Minute,
+					ExpirySweepInterval: time.Minute,
 				},
 				RateLimiter: RateLimiterConfig{
 					MaxTokens:      2,
@@ -45,6 +57,32 @@ func TestConfig_New(t *testing.T) {
 				MongoDB: MongoDBConfig{
 					Seed: false,
 				},
+				Recurring: RecurringSchedulerConfig{
+					Interval:  30 * time.Second,
+					BatchSize: 100,
+				},
+				Idempotency: IdempotencyConfig{
+					Retention: 24 * time.Hour,
+				},
+				BulkImport: BulkImportConfig{
+					BatchSize: 500,
+				},
+				Phone: PhoneConfig{
+					DefaultRegion: "TR",
+				},
+				SMS: SMSConfig{
+					MaxSegments: 6,
+				},
+				ContentFilter: ContentFilterConfig{
+					Rules: []ContentRuleConfig{
+						{Name: "internal-url", Pattern: `(?i)https?://[^\s/]*\.(local|internal|corp)\b`},
+						{Name: "profanity", Keywords: []string{"damn", "bloody hell"}},
+					},
+				},
+				Stats: StatsConfig{
+					Window:   time.Hour,
+					CacheTTL: 5 * time.Second,
+				},
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type ContentFilterConfig struct {
+	Rules []ContentRuleConfig `mapstructure:"rules"`
+}
+
+// ContentRuleConfig is a named rule that rejects content matching its regular
+// expression or containing one of its keywords. Keywords match whole words
+// regardless of case.
+type ContentRuleConfig struct {
+	Name     string   `mapstructure:"name"`
+	Pattern  string   `mapstructure:"pattern"`
+	Keywords []string `mapstructure:"keywords"`
+}
+
+type contentRule struct {
+	name    string
+	matcher *regexp.Regexp
+}
+
+// ContentFilter holds the compiled banned-content rules. Rules can be replaced
+// at runtime with Reload, e.g. when the config file changes.
+type ContentFilter struct {
+	mu    sync.RWMutex
+	rules []contentRule
+}
+
+func NewContentFilter(cfg ContentFilterConfig) (*ContentFilter, error) {
+	filter := &ContentFilter{}
+	if err := filter.Reload(cfg); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// Reload compiles the rules and swaps them in. When a rule does not compile
+// the filter keeps its current rules.
+func (f *ContentFilter) Reload(cfg ContentFilterConfig) error {
+	rules, err := compileContentRules(cfg.Rules)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.rules = rules
+	f.mu.Unlock()
+
+	return nil
+}
+
+// Match returns the name of the first rule the content breaks.
+func (f *ContentFilter) Match(content string) (string, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, rule := range f.rules {
+		if rule.matcher.MatchString(content) {
+			return rule.name, true
+		}
+	}
+
+	return "", false
+}
+
+// contentFilterReason is recorded on messages rejected by a content rule.
+func contentFilterReason(rule string) string {
+	return "content matched filter rule " + rule
+}
+
+func compileContentRules(configs []ContentRuleConfig) ([]contentRule, error) {
+	var rules []contentRule
+	for i, cfg := range configs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("content filter rule %d has no name", i)
+		}
+		if cfg.Pattern == "" && len(cfg.Keywords) == 0 {
+			return nil, fmt.Errorf("content filter rule %s has no pattern or keywords", cfg.Name)
+		}
+
+		if cfg.Pattern != "" {
+			matcher, err := regexp.Compile(cfg.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("content filter rule %s: %w", cfg.Name, err)
+			}
+			rules = append(rules, contentRule{name: cfg.Name, matcher: matcher})
+		}
+
+		var keywords []string
+		for _, keyword := range cfg.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, regexp.QuoteMeta(keyword))
+			}
+		}
+		if len(keywords) > 0 {
+			matcher := regexp.MustCompile(`(?i)(?:^|[^\pL\pN])(?:` + strings.Join(keywords, "|") + `)(?:$|[^\pL\pN])`)
+			rules = append(rules, contentRule{name: cfg.Name, matcher: matcher})
+		}
+	}
+
+	return rules, nil
+}
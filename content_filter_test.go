@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentFilter_Match(t *testing.T) {
+	filter, err := NewContentFilter(ContentFilterConfig{
+		Rules: []ContentRuleConfig{
+			{Name: "internal-url", Pattern: `(?i)https?://[^\s]*\.(?:local|internal)\b`},
+			{Name: "profanity", Keywords: []string{"damn", "heck off"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		content     string
+		wantRule    string
+		wantMatched bool
+	}{
+		{
+			name:        "should match a regular expression rule",
+			content:     "Reset your password at https://intranet.example.local/reset",
+			wantRule:    "internal-url",
+			wantMatched: true,
+		},
+		{
+			name:        "should match keywords regardless of case",
+			content:     "Well, DAMN.",
+			wantRule:    "profanity",
+			wantMatched: true,
+		},
+		{
+			name:        "should match keywords with spaces",
+			content:     "Just heck off",
+			wantRule:    "profanity",
+			wantMatched: true,
+		},
+		{
+			name:        "should not match keywords inside other words",
+			content:     "The damnation of Faust is on tonight",
+			wantMatched: false,
+		},
+		{
+			name:        "should not match clean content",
+			content:     "Your verification code is: 729384",
+			wantMatched: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, matched := filter.Match(tt.content)
+			assert.Equal(t, tt.wantMatched, matched)
+			assert.Equal(t, tt.wantRule, rule)
+		})
+	}
+}
+
+func TestContentFilter_Reload(t *testing.T) {
+	filter, err := NewContentFilter(ContentFilterConfig{})
+	assert.NoError(t, err)
+
+	_, matched := filter.Match("visit http://wiki.internal")
+	assert.False(t, matched)
+
+	assert.NoError(t, filter.Reload(ContentFilterConfig{Rules: []ContentRuleConfig{{Name: "internal-url", Pattern: `\.internal\b`}}}))
+	rule, matched := filter.Match("visit http://wiki.internal")
+	assert.True(t, matched)
+	assert.Equal(t, "internal-url", rule)
+
+	// A broken rule set is rejected and the current rules stay in place.
+	assert.Error(t, filter.Reload(ContentFilterConfig{Rules: []ContentRuleConfig{{Name: "broken", Pattern: `(`}}}))
+	assert.Error(t, filter.Reload(ContentFilterConfig{Rules: []ContentRuleConfig{{Name: "empty"}}}))
+	assert.Error(t, filter.Reload(ContentFilterConfig{Rules: []ContentRuleConfig{{Pattern: "x"}}}))
+	_, matched = filter.Match("visit http://wiki.internal")
+	assert.True(t, matched)
+}
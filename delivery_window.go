@@ -0,0 +1,54 @@
+package main
+
+import (
+	"time"
+)
+
+const deliveryWindowTimeLayout = "15:04"
+
+// DeliveryWindow is the local time of day a message may be delivered in, e.g.
+// 09:00 to 21:00 in Europe/Istanbul. A window whose end is before its start
+// wraps around midnight, e.g. 20:00 to 02:00.
+type DeliveryWindow struct {
+	Timezone string `bson:"timezone" json:"timezone" validate:"required,timezone"`
+	Start    string `bson:"start" json:"start" validate:"required,datetime=15:04"`
+	End      string `bson:"end" json:"end" validate:"required,datetime=15:04,nefield=Start"`
+}
+
+// NextOpening returns t itself when t is inside the window, and otherwise the
+// first time after t at which the window opens.
+func (dw DeliveryWindow) NextOpening(t time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(dw.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	start, err := time.Parse(deliveryWindowTimeLayout, dw.Start)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	end, err := time.Parse(deliveryWindowTimeLayout, dw.End)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := t.In(location)
+	year, month, day := local.Date()
+	opensToday := time.Date(year, month, day, start.Hour(), start.Minute(), 0, 0, location)
+	closesToday := time.Date(year, month, day, end.Hour(), end.Minute(), 0, 0, location)
+
+	if opensToday.Before(closesToday) {
+		if !local.Before(opensToday) && local.Before(closesToday) {
+			return t, nil
+		}
+	} else if !local.Before(opensToday) || local.Before(closesToday) {
+		return t, nil
+	}
+
+	if local.Before(opensToday) {
+		return opensToday, nil
+	}
+
+	return time.Date(year, month, day+1, start.Hour(), start.Minute(), 0, 0, location), nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryWindow_NextOpening(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	assert.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		window  DeliveryWindow
+		at      time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:   "inside window returns the given time",
+			window: DeliveryWindow{Timezone: "Europe/Istanbul", Start: "09:00", End: "21:00"},
+			at:     time.Date(2025, 6, 1, 12, 30, 0, 0, istanbul),
+			want:   time.Date(2025, 6, 1, 12, 30, 0, 0, istanbul),
+		},
+		{
+			name:   "before opening defers to today's opening",
+			window: DeliveryWindow{Timezone: "Europe/Istanbul", Start: "09:00", End: "21:00"},
+			at:     time.Date(2025, 6, 1, 3, 0, 0, 0, istanbul),
+			want:   time.Date(2025, 6, 1, 9, 0, 0, 0, istanbul),
+		},
+		{
+			name:   "at closing defers to tomorrow's opening",
+			window: DeliveryWindow{Timezone: "Europe/Istanbul", Start: "09:00", End: "21:00"},
+			at:     time.Date(2025, 6, 1, 21, 0, 0, 0, istanbul),
+			want:   time.Date(2025, 6, 2, 9, 0, 0, 0, istanbul),
+		},
+		{
+			name:   "time is evaluated in the window's timezone",
+			window: DeliveryWindow{Timezone: "America/New_York", Start: "09:00", End: "21:00"},
+			at:     time.Date(2025, 6, 1, 12, 0, 0, 0, istanbul),
+			want:   time.Date(2025, 6, 1, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:   "overnight window contains times after midnight",
+			window: DeliveryWindow{Timezone: "Europe/Istanbul", Start: "20:00", End: "02:00"},
+			at:     time.Date(2025, 6, 1, 1, 0, 0, 0, istanbul),
+			want:   time.Date(2025, 6, 1, 1, 0, 0, 0, istanbul),
+		},
+		{
+			name:   "overnight window defers to its evening opening",
+			window: DeliveryWindow{Timezone: "Europe/Istanbul", Start: "20:00", End: "02:00"},
+			at:     time.Date(2025, 6, 1, 12, 0, 0, 0, istanbul),
+			want:   time.Date(2025, 6, 1, 20, 0, 0, 0, istanbul),
+		},
+		{
+			name:    "unknown timezone returns error",
+			window:  DeliveryWindow{Timezone: "Mars/Olympus", Start: "09:00", End: "21:00"},
+			at:      time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.window.NextOpening(tt.at)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,17 +14,169 @@ import (
 )
 
 type MessageService interface {
-	RetrieveSentMessages() ([]Message, error)
+	RetrieveSentMessages(ctx context.Context, limit int, pageToken string) (*MessagePage, error)
+	ListMessages(ctx context.Context, filter MessageFilter) (*MessagePage, error)
+	RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error)
+	CreateMessage(ctx context.Context, req CreateMessageRequest) (primitive.ObjectID, error)
+	ImportMessages(ctx context.Context, format string, body io.Reader) (*BulkImportResponse, error)
+	CancelMessage(ctx context.Context, messageID primitive.ObjectID) error
+	UpdateMessage(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) (*Message, error)
+	RetrieveFailedMessages(ctx context.Context, page, limit int) (*FailedMessagesResponse, error)
+	RequeueMessage(ctx context.Context, messageID primitive.ObjectID) error
+	RequeueStuckMessage(ctx context.Context, messageID primitive.ObjectID) error
+	RequeueFailedMessages(ctx context.Context, req RequeueFailedMessagesRequest) (int64, error)
+	ValidateMessage(req CreateMessageRequest) *MessageVerdict
 }
 
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
 type Message struct {
-	ID                       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	WebhookResponseMessageID string             `bson:"webhook_response_message_id" json:"webhook_response_message_id"`
-	Content                  string             `bson:"content" json:"content" validate:"max=160,min=1"`
-	RecipientPhoneNumber     string             `bson:"recipient_phone_number" json:"recipient_phone_number"`
-	Status                   string             `bson:"status" json:"status"`
-	CreatedAt                time.Time          `bson:"created_at" json:"created_at"`
-	SentAt                   time.Time          `bson:"sent_at" json:"sent_at"`
+	ID                       primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	WebhookResponseMessageID string              `bson:"webhook_response_message_id" json:"webhook_response_message_id"`
+	Content                  string              `bson:"content" json:"content" validate:"required_without=TemplateID,segments"`
+	Encoding                 string              `bson:"encoding,omitempty" json:"encoding,omitempty"` // GSM-7 or UCS-2
+	Segments                 int                 `bson:"segments,omitempty" json:"segments,omitempty"` // SMS parts the content is split into
+	RecipientPhoneNumber     string              `bson:"recipient_phone_number" json:"recipient_phone_number" validate:"required,phone"`
+	Status                   string              `bson:"status" json:"status"`
+	Priority                 string              `bson:"priority,omitempty" json:"priority,omitempty" validate:"omitempty,oneof=high normal low"`
+	PriorityRank             int                 `bson:"priority_rank,omitempty" json:"-"`
+	CreatedAt                time.Time           `bson:"created_at" json:"created_at"`
+	ScheduledAt              time.Time           `bson:"scheduled_at" json:"scheduled_at"`
+	RecurringScheduleID      *primitive.ObjectID `bson:"recurring_schedule_id,omitempty" json:"recurring_schedule_id,omitempty"`
+	CampaignID               *primitive.ObjectID `bson:"campaign_id,omitempty" json:"campaign_id,omitempty"`
+	DuplicateOf              *primitive.ObjectID `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"`
+	Held                     bool                `bson:"held,omitempty" json:"held,omitempty"`
+	CampaignCancelled        bool                `bson:"campaign_cancelled,omitempty" json:"-"`
+	WorkerID                 string              `bson:"worker_id,omitempty" json:"worker_id,omitempty"`
+	ProcessingStartedAt      time.Time           `bson:"processing_started_at,omitempty" json:"processing_started_at,omitzero"`
+	DispatchStartedAt        time.Time           `bson:"dispatch_started_at,omitempty" json:"dispatch_started_at,omitzero"`
+	Attempts                 int                 `bson:"attempts" json:"attempts"`
+	NextAttemptAt            time.Time           `bson:"next_attempt_at,omitempty" json:"next_attempt_at,omitzero"`
+	LastError                string              `bson:"err,omitempty" json:"last_error,omitempty"`
+	FailureClass             string              `bson:"failure_class,omitempty" json:"failure_class,omitempty"`
+	FailureStatusCode        int                 `bson:"failure_status_code,omitempty" json:"failure_status_code,omitempty"`
+	FailedAt                 time.Time           `bson:"failed_at,omitempty" json:"failed_at,omitzero"`
+	CancelledAt              time.Time           `bson:"cancelled_at,omitempty" json:"cancelled_at,omitzero"`
+	ExpiresAt                *time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty" validate:"omitempty,gtfield=ScheduledAt"`
+	ExpiredAt                time.Time           `bson:"expired_at,omitempty" json:"expired_at,omitzero"`
+	DeliveryWindow           *DeliveryWindow     `bson:"delivery_window,omitempty" json:"delivery_window,omitempty"`
+	DeferredUntil            time.Time           `bson:"deferred_until,omitempty" json:"deferred_until,omitzero"`
+	Deferrals                int                 `bson:"deferrals,omitempty" json:"deferrals,omitempty"`
+	SentAt                   time.Time           `bson:"sent_at" json:"sent_at"`
+	ExternalID               string              `bson:"external_id,omitempty" json:"external_id,omitempty" validate:"omitempty,max=100"`
+	Tags                     []string            `bson:"tags,omitempty" json:"tags,omitempty" validate:"omitempty,max=10,unique,dive,required,max=50"`
+	Metadata                 map[string]string   `bson:"metadata,omitempty" json:"metadata,omitempty" validate:"omitempty,max=20,dive,keys,required,max=40,excludesall=.$,endkeys,max=500"`
+	TemplateID               *primitive.ObjectID `bson:"template_id,omitempty" json:"template_id,omitempty"`
+	TemplateVariables        map[string]string   `bson:"template_variables,omitempty" json:"template_variables,omitempty"`
+	History                  []DeliveryAttempt   `bson:"history,omitempty" json:"history,omitempty"`
+}
+
+// DeliveryAttempt is one webhook call made for a message, recorded in the
+// message's history whether it succeeded or not.
+type DeliveryAttempt struct {
+	AttemptedAt       time.Time `bson:"attempted_at" json:"attempted_at"`
+	WorkerID          string    `bson:"worker_id" json:"worker_id"`
+	StatusCode        int       `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	LatencyMs         int64     `bson:"latency_ms" json:"latency_ms"`
+	ProviderMessageID string    `bson:"provider_message_id,omitempty" json:"provider_message_id,omitempty"`
+	Error             string    `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+type CreateMessageRequest struct {
+	Content              string            `json:"content,omitempty"`
+	RecipientPhoneNumber string            `json:"recipient_phone_number"`
+	Priority             string            `json:"priority,omitempty"`        // high, normal or low; defaults to normal
+	ScheduledAt          *time.Time        `json:"scheduled_at,omitempty"`    // defaults to now when omitted
+	ExpiresAt            *time.Time        `json:"expires_at,omitempty"`      // the message is not delivered after this time
+	DeliveryWindow       *DeliveryWindow   `json:"delivery_window,omitempty"` // local hours the message may be delivered in
+	TemplateID           string            `json:"template_id,omitempty"`     // used instead of content, rendered at dispatch time
+	Variables            map[string]string `json:"variables,omitempty"`       // values for the template placeholders
+	ExternalID           string            `json:"external_id,omitempty"`     // caller's reference, e.g. an order ID
+	Tags                 []string          `json:"tags,omitempty"`            // labels to group messages by
+	Metadata             map[string]string `json:"metadata,omitempty"`        // forwarded to the webhook when enabled
+	IdempotencyKey       string            `json:"-"`                         // taken from the Idempotency-Key header
+}
+
+type CreateMessageResponse struct {
+	ID string `json:"id"`
+}
+
+// MessageVerdict tells whether a message would be accepted and sent, and why
+// not otherwise.
+type MessageVerdict struct {
+	Valid                bool   `json:"valid"`
+	Reason               string `json:"reason,omitempty"`
+	Rule                 string `json:"rule,omitempty"`                   // content filter rule the content matched
+	RecipientPhoneNumber string `json:"recipient_phone_number,omitempty"` // normalized to E.164
+	Encoding             string `json:"encoding,omitempty"`
+	Segments             int    `json:"segments,omitempty"`
+}
+
+// BulkImportResponse reports the outcome of a bulk upload. Rows listed in Errors
+// were not enqueued; every other row was.
+type BulkImportResponse struct {
+	Total    int            `json:"total"`
+	Inserted int            `json:"inserted"`
+	Errors   []BulkRowError `json:"errors"`
+}
+
+type BulkRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// UpdateMessageRequest edits an unsent message. Only the fields present in the
+// request are changed.
+type UpdateMessageRequest struct {
+	Content              *string    `json:"content,omitempty"`
+	RecipientPhoneNumber *string    `json:"recipient_phone_number,omitempty"`
+	ScheduledAt          *time.Time `json:"scheduled_at,omitempty"`
+}
+
+// MessageFilter narrows a message listing. Zero values are not applied.
+type MessageFilter struct {
+	Status               string
+	RecipientPhoneNumber string
+	ExternalID           string
+	Tag                  string
+	CreatedAfter         *time.Time
+	CreatedBefore        *time.Time
+	SentAfter            *time.Time
+	SentBefore           *time.Time
+	SortBy               string // "created_at" (default) or "sent_at"
+	Limit                int
+	PageToken            string
+}
+
+type MessagePage struct {
+	Messages      []Message `json:"messages"`
+	NextPageToken string    `json:"next_page_token,omitempty"`
+}
+
+type FailedMessagesResponse struct {
+	Messages []Message `json:"messages"`
+	Page     int       `json:"page"`
+	Limit    int       `json:"limit"`
+	Total    int64     `json:"total"`
+}
+
+// RequeueFailedMessagesRequest selects failed messages to move back to unsent.
+// Empty fields are not applied, so an empty request requeues every failed message.
+type RequeueFailedMessagesRequest struct {
+	FailureClass         string     `json:"failure_class,omitempty"`
+	RecipientPhoneNumber string     `json:"recipient_phone_number,omitempty"`
+	FailedAfter          *time.Time `json:"failed_after,omitempty"`
+	FailedBefore         *time.Time `json:"failed_before,omitempty"`
+}
+
+type RequeueFailedMessagesResponse struct {
+	Requeued int64 `json:"requeued"`
 }
 
 type MessageHandler struct {
@@ -34,26 +191,504 @@ func NewMessageHandler(ms MessageService) *MessageHandler {
 
 func (h *MessageHandler) RegisterRoutes(app *fiber.App) {
 	app.Get("/sent-messages", h.RetriveSentMessages)
+	app.Get("/messages", h.ListMessages)
+	app.Post("/messages", h.CreateMessage)
+	app.Post("/messages/bulk", h.ImportMessages)
+	app.Post("/messages/validate", h.ValidateMessage)
+	app.Get("/messages/failed", h.RetrieveFailedMessages)
+	app.Post("/messages/failed/requeue", h.RequeueFailedMessages)
+	app.Get("/messages/:id", h.RetrieveMessage)
+	app.Patch("/messages/:id", h.UpdateMessage)
+	app.Delete("/messages/:id", h.CancelMessage)
+	app.Post("/messages/:id/retry", h.RetryMessage)
+	app.Post("/messages/:id/requeue-stuck", h.RequeueStuckMessage)
 }
 
 // RetriveSentMessages godoc
-// @Summary Retrieve all sent messages
-// @Description Get all successfully sent messages
+// @Summary Retrieve sent messages
+// @Description Get successfully sent messages, most recently sent first, one page at a time
 // @Tags messages
 // @Accept json
 // @Produce json
-// @Success 200 {array} Message
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param page_token query string false "Token of the page to fetch, taken from next_page_token"
+// @Success 200 {object} MessagePage
+// @Failure 400 {object} map[string]string "Invalid pagination parameters"
 // @Failure 404 {object} nil "No sent messages found"
 // @Failure 500 {object} nil "Internal server error"
 // @Router /sent-messages [get]
 func (h *MessageHandler) RetriveSentMessages(c *fiber.Ctx) error {
-	sentMessages, err := h.messageService.RetrieveSentMessages()
+	limit := c.QueryInt("limit", defaultPageLimit)
+	if limit < 1 || limit > maxPageLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid pagination parameters",
+		})
+	}
+
+	sentMessages, err := h.messageService.RetrieveSentMessages(c.UserContext(), limit, c.Query("page_token"))
 	if err != nil {
 		if errors.Is(err, ErrDocumentNotFound) {
 			return c.SendStatus(fiber.StatusNotFound)
 		}
+		if errors.Is(err, ErrInvalidPageToken) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
 		return c.SendStatus(fiber.StatusInternalServerError)
 	}
 
 	return c.JSON(sentMessages)
 }
+
+// ListMessages godoc
+// @Summary List messages
+// @Description Get messages matching the given filters, newest first, one page at a time
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param status query string false "Message status"
+// @Param recipient query string false "Recipient phone number"
+// @Param external_id query string false "External reference ID"
+// @Param tag query string false "Tag the message carries"
+// @Param created_after query string false "Created at or after (RFC3339)"
+// @Param created_before query string false "Created at or before (RFC3339)"
+// @Param sent_after query string false "Sent at or after (RFC3339)"
+// @Param sent_before query string false "Sent at or before (RFC3339)"
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Param page_token query string false "Token of the page to fetch, taken from next_page_token"
+// @Success 200 {object} MessagePage
+// @Failure 400 {object} map[string]string "Invalid filter or pagination parameters"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages [get]
+func (h *MessageHandler) ListMessages(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultPageLimit)
+	if limit < 1 || limit > maxPageLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid pagination parameters",
+		})
+	}
+
+	filter, err := parseMessageFilter(c, limit)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	messages, err := h.messageService.ListMessages(c.UserContext(), filter)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPageToken) || errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(messages)
+}
+
+func parseMessageFilter(c *fiber.Ctx, limit int) (MessageFilter, error) {
+	filter := MessageFilter{
+		Status:               c.Query("status"),
+		RecipientPhoneNumber: c.Query("recipient"),
+		ExternalID:           c.Query("external_id"),
+		Tag:                  c.Query("tag"),
+		Limit:                limit,
+		PageToken:            c.Query("page_token"),
+	}
+
+	timeParams := map[string]**time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+		"sent_after":     &filter.SentAfter,
+		"sent_before":    &filter.SentBefore,
+	}
+	for param, target := range timeParams {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s, expected RFC3339 time", param)
+		}
+		*target = &parsed
+	}
+
+	return filter, nil
+}
+
+// CreateMessage godoc
+// @Summary Create a new message
+// @Description Enqueue a new message to be sent by the worker pool
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Client-chosen key; repeating a request with the same key returns the original response"
+// @Param message body CreateMessageRequest true "Message to enqueue"
+// @Success 201 {object} CreateMessageResponse
+// @Failure 400 {object} map[string]string "Invalid request body or validation error"
+// @Failure 409 {object} map[string]string "A request with the same idempotency key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency key was used with a different request"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages [post]
+func (h *MessageHandler) CreateMessage(c *fiber.Ctx) error {
+	var req CreateMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.IdempotencyKey = c.Get(idempotencyKeyHeader)
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid Idempotency-Key header",
+		})
+	}
+
+	id, err := h.messageService.CreateMessage(c.UserContext(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrValidationFailed):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, ErrIdempotencyKeyInProgress):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, ErrIdempotencyKeyReused):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CreateMessageResponse{
+		ID: id.Hex(),
+	})
+}
+
+// ImportMessages godoc
+// @Summary Import messages in bulk
+// @Description Enqueue many messages from an NDJSON (one CreateMessageRequest-like object per line, plus optional metadata) or CSV (header with content, recipient_phone_number, optional scheduled_at, external_id, tags (semicolon separated) and metadata.<key> columns) upload. Every row is validated on its own; invalid rows are reported and skipped.
+// @Tags messages
+// @Accept application/x-ndjson
+// @Accept text/csv
+// @Produce json
+// @Param file body string true "NDJSON or CSV rows"
+// @Success 200 {object} BulkImportResponse
+// @Failure 400 {object} map[string]string "Unreadable upload, e.g. a CSV header without required columns"
+// @Failure 415 {object} map[string]string "Unsupported content type"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/bulk [post]
+func (h *MessageHandler) ImportMessages(c *fiber.Ctx) error {
+	format, ok := bulkFormat(c.Get(fiber.HeaderContentType))
+	if !ok {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Content-Type must be application/x-ndjson or text/csv",
+		})
+	}
+
+	// With StreamRequestBody the upload is read while it arrives instead of
+	// being buffered in full first.
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	report, err := h.messageService.ImportMessages(c.UserContext(), format, body)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBulkUpload) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(report)
+}
+
+func bulkFormat(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	switch mediaType {
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return BulkFormatNDJSON, true
+	case "text/csv":
+		return BulkFormatCSV, true
+	default:
+		return "", false
+	}
+}
+
+// ValidateMessage godoc
+// @Summary Validate a message
+// @Description Preview whether a message would be accepted and sent: request validation, the content filter, and the encoding and segment count of its content. Nothing is stored.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param message body CreateMessageRequest true "Message to validate"
+// @Success 200 {object} MessageVerdict
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Router /messages/validate [post]
+func (h *MessageHandler) ValidateMessage(c *fiber.Ctx) error {
+	var req CreateMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	return c.JSON(h.messageService.ValidateMessage(req))
+}
+
+// RetrieveFailedMessages godoc
+// @Summary Retrieve failed messages
+// @Description Get the dead-letter view of messages that exhausted their delivery attempts, newest failure first
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} FailedMessagesResponse
+// @Failure 400 {object} map[string]string "Invalid pagination parameters"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/failed [get]
+func (h *MessageHandler) RetrieveFailedMessages(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", defaultPageLimit)
+	if page < 1 || limit < 1 || limit > maxPageLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid pagination parameters",
+		})
+	}
+
+	failedMessages, err := h.messageService.RetrieveFailedMessages(c.UserContext(), page, limit)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(failedMessages)
+}
+
+// RetrieveMessage godoc
+// @Summary Retrieve a message
+// @Description Get a single message with its delivery attempt history
+// @Tags messages
+// @Produce json
+// @Param id path string true "Message ID"
+// @Success 200 {object} Message
+// @Failure 400 {object} map[string]string "Invalid message ID"
+// @Failure 404 {object} nil "Message not found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/{id} [get]
+func (h *MessageHandler) RetrieveMessage(c *fiber.Ctx) error {
+	messageID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidMessageID.Error(),
+		})
+	}
+
+	message, err := h.messageService.RetrieveMessage(c.UserContext(), messageID)
+	if err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(message)
+}
+
+// UpdateMessage godoc
+// @Summary Edit a scheduled message
+// @Description Change the content, recipient or scheduled time of a message that has not been picked up by a worker yet. The content of a template message cannot be changed, and the scheduled time must be before the message expires
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Message ID"
+// @Param message body UpdateMessageRequest true "Fields to change"
+// @Success 200 {object} Message
+// @Failure 400 {object} map[string]string "Invalid message ID, request body or validation error"
+// @Failure 404 {object} nil "Message not found"
+// @Failure 409 {object} map[string]string "Message is no longer unsent"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/{id} [patch]
+func (h *MessageHandler) UpdateMessage(c *fiber.Ctx) error {
+	messageID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidMessageID.Error(),
+		})
+	}
+
+	var req UpdateMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	message, err := h.messageService.UpdateMessage(c.UserContext(), messageID, req)
+	if err != nil {
+		return h.editErrorResponse(c, err)
+	}
+
+	return c.JSON(message)
+}
+
+// CancelMessage godoc
+// @Summary Cancel a scheduled message
+// @Description Move a message that has not been picked up by a worker yet to cancelled so it is never sent
+// @Tags messages
+// @Param id path string true "Message ID"
+// @Success 204 {object} nil "Cancelled"
+// @Failure 400 {object} map[string]string "Invalid message ID"
+// @Failure 404 {object} nil "Message not found"
+// @Failure 409 {object} map[string]string "Message is no longer unsent"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/{id} [delete]
+func (h *MessageHandler) CancelMessage(c *fiber.Ctx) error {
+	messageID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidMessageID.Error(),
+		})
+	}
+
+	if err := h.messageService.CancelMessage(c.UserContext(), messageID); err != nil {
+		return h.editErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *MessageHandler) editErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrValidationFailed):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, ErrDocumentNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, ErrMessageNotEditable):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
+
+// RetryMessage godoc
+// @Summary Retry a failed message
+// @Description Move a failed message back to unsent with a fresh attempt budget
+// @Tags messages
+// @Param id path string true "Message ID"
+// @Success 204 {object} nil "Requeued"
+// @Failure 400 {object} map[string]string "Invalid message ID"
+// @Failure 404 {object} nil "Failed message not found"
+// @Failure 409 {object} map[string]string "Message belongs to a paused or cancelled campaign"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/{id}/retry [post]
+func (h *MessageHandler) RetryMessage(c *fiber.Ctx) error {
+	messageID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidMessageID.Error(),
+		})
+	}
+
+	if err := h.messageService.RequeueMessage(c.UserContext(), messageID); err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		if errors.Is(err, ErrMessageHeld) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RequeueStuckMessage godoc
+// @Summary Requeue a stuck message
+// @Description Move a stuck message back to unsent once it is known not to have been delivered. A send recorded by the earlier attempt is still picked up instead of sending the message again
+// @Tags messages
+// @Param id path string true "Message ID"
+// @Success 204 {object} nil "Requeued"
+// @Failure 400 {object} map[string]string "Invalid message ID"
+// @Failure 404 {object} nil "Stuck message not found"
+// @Failure 409 {object} map[string]string "Message belongs to a paused or cancelled campaign"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/{id}/requeue-stuck [post]
+func (h *MessageHandler) RequeueStuckMessage(c *fiber.Ctx) error {
+	messageID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidMessageID.Error(),
+		})
+	}
+
+	if err := h.messageService.RequeueStuckMessage(c.UserContext(), messageID); err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		if errors.Is(err, ErrMessageHeld) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RequeueFailedMessages godoc
+// @Summary Requeue failed messages in bulk
+// @Description Move every failed message matching the filter back to unsent
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param filter body RequeueFailedMessagesRequest true "Filter for failed messages to requeue"
+// @Success 200 {object} RequeueFailedMessagesResponse
+// @Failure 400 {object} map[string]string "Invalid request body or recipient phone number"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /messages/failed/requeue [post]
+func (h *MessageHandler) RequeueFailedMessages(c *fiber.Ctx) error {
+	var req RequeueFailedMessagesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	requeued, err := h.messageService.RequeueFailedMessages(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(RequeueFailedMessagesResponse{
+		Requeued: requeued,
+	})
+}
@@ -10,8 +10,11 @@
 package main
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
 
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,17 +42,178 @@ func (m *MockMessageService) EXPECT() *MockMessageServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelMessage mocks base method.
+func (m *MockMessageService) CancelMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelMessage", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelMessage indicates an expected call of CancelMessage.
+func (mr *MockMessageServiceMockRecorder) CancelMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelMessage", reflect.TypeOf((*MockMessageService)(nil).CancelMessage), ctx, messageID)
+}
+
+// CreateMessage mocks base method.
+func (m *MockMessageService) CreateMessage(ctx context.Context, req CreateMessageRequest) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, req)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockMessageServiceMockRecorder) CreateMessage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockMessageService)(nil).CreateMessage), ctx, req)
+}
+
+// ImportMessages mocks base method.
+func (m *MockMessageService) ImportMessages(ctx context.Context, format string, body io.Reader) (*BulkImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportMessages", ctx, format, body)
+	ret0, _ := ret[0].(*BulkImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportMessages indicates an expected call of ImportMessages.
+func (mr *MockMessageServiceMockRecorder) ImportMessages(ctx, format, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportMessages", reflect.TypeOf((*MockMessageService)(nil).ImportMessages), ctx, format, body)
+}
+
+// ListMessages mocks base method.
+func (m *MockMessageService) ListMessages(ctx context.Context, filter MessageFilter) (*MessagePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", ctx, filter)
+	ret0, _ := ret[0].(*MessagePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockMessageServiceMockRecorder) ListMessages(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockMessageService)(nil).ListMessages), ctx, filter)
+}
+
+// RequeueFailedMessages mocks base method.
+func (m *MockMessageService) RequeueFailedMessages(ctx context.Context, req RequeueFailedMessagesRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueFailedMessages", ctx, req)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueFailedMessages indicates an expected call of RequeueFailedMessages.
+func (mr *MockMessageServiceMockRecorder) RequeueFailedMessages(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueFailedMessages", reflect.TypeOf((*MockMessageService)(nil).RequeueFailedMessages), ctx, req)
+}
+
+// RequeueMessage mocks base method.
+func (m *MockMessageService) RequeueMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueMessage", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueMessage indicates an expected call of RequeueMessage.
+func (mr *MockMessageServiceMockRecorder) RequeueMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueMessage", reflect.TypeOf((*MockMessageService)(nil).RequeueMessage), ctx, messageID)
+}
+
+// RequeueStuckMessage mocks base method.
+func (m *MockMessageService) RequeueStuckMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueStuckMessage", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueStuckMessage indicates an expected call of RequeueStuckMessage.
+func (mr *MockMessageServiceMockRecorder) RequeueStuckMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueStuckMessage", reflect.TypeOf((*MockMessageService)(nil).RequeueStuckMessage), ctx, messageID)
+}
+
+// RetrieveFailedMessages mocks base method.
+func (m *MockMessageService) RetrieveFailedMessages(ctx context.Context, page, limit int) (*FailedMessagesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveFailedMessages", ctx, page, limit)
+	ret0, _ := ret[0].(*FailedMessagesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveFailedMessages indicates an expected call of RetrieveFailedMessages.
+func (mr *MockMessageServiceMockRecorder) RetrieveFailedMessages(ctx, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFailedMessages", reflect.TypeOf((*MockMessageService)(nil).RetrieveFailedMessages), ctx, page, limit)
+}
+
+// RetrieveMessage mocks base method.
+func (m *MockMessageService) RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveMessage", ctx, messageID)
+	ret0, _ := ret[0].(*Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveMessage indicates an expected call of RetrieveMessage.
+func (mr *MockMessageServiceMockRecorder) RetrieveMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveMessage", reflect.TypeOf((*MockMessageService)(nil).RetrieveMessage), ctx, messageID)
+}
+
 // RetrieveSentMessages mocks base method.
-func (m *MockMessageService) RetrieveSentMessages() ([]Message, error) {
+func (m *MockMessageService) RetrieveSentMessages(ctx context.Context, limit int, pageToken string) (*MessagePage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RetrieveSentMessages")
-	ret0, _ := ret[0].([]Message)
+	ret := m.ctrl.Call(m, "RetrieveSentMessages", ctx, limit, pageToken)
+	ret0, _ := ret[0].(*MessagePage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RetrieveSentMessages indicates an expected call of RetrieveSentMessages.
-func (mr *MockMessageServiceMockRecorder) RetrieveSentMessages() *gomock.Call {
+func (mr *MockMessageServiceMockRecorder) RetrieveSentMessages(ctx, limit, pageToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveSentMessages", reflect.TypeOf((*MockMessageService)(nil).RetrieveSentMessages), ctx, limit, pageToken)
+}
+
+// UpdateMessage mocks base method.
+func (m *MockMessageService) UpdateMessage(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) (*Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, messageID, req)
+	ret0, _ := ret[0].(*Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockMessageServiceMockRecorder) UpdateMessage(ctx, messageID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockMessageService)(nil).UpdateMessage), ctx, messageID, req)
+}
+
+// ValidateMessage mocks base method.
+func (m *MockMessageService) ValidateMessage(req CreateMessageRequest) *MessageVerdict {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateMessage", req)
+	ret0, _ := ret[0].(*MessageVerdict)
+	return ret0
+}
+
+// ValidateMessage indicates an expected call of ValidateMessage.
+func (mr *MockMessageServiceMockRecorder) ValidateMessage(req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveSentMessages", reflect.TypeOf((*MockMessageService)(nil).RetrieveSentMessages))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateMessage", reflect.TypeOf((*MockMessageService)(nil).ValidateMessage), req)
 }
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -34,7 +40,8 @@ func TestHandler_RetrieveSentMessages(t *testing.T) {
 		return
 	}
 
-	sampleSentMessageContentByte, err := json.Marshal(sampleSentMessages)
+	sampleSentMessagesPage := &MessagePage{Messages: sampleSentMessages, NextPageToken: "next-token"}
+	sampleSentMessageContentByte, err := json.Marshal(sampleSentMessagesPage)
 	if err != nil {
 		assert.Fail(t, "Failed to marshal sample sent messages")
 		return
@@ -53,7 +60,25 @@ func TestHandler_RetrieveSentMessages(t *testing.T) {
 			wantStatus: fiber.StatusOK,
 			wantBody:   string(sampleSentMessageContentByte),
 			beforeSuite: func() {
-				mockService.EXPECT().RetrieveSentMessages().Return(sampleSentMessages, nil)
+				mockService.EXPECT().RetrieveSentMessages(gomock.Any(), defaultPageLimit, "").Return(sampleSentMessagesPage, nil)
+			},
+		},
+		{
+			name:       "should pass pagination parameters to service",
+			url:        sentMessagesPath + "?limit=5&page_token=next-token",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"messages":[]}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveSentMessages(gomock.Any(), 5, "next-token").Return(&MessagePage{Messages: []Message{}}, nil)
+			},
+		},
+		{
+			name:       "should return error when page token is invalid",
+			url:        sentMessagesPath + "?page_token=broken",
+			wantStatus: fiber.StatusBadRequest,
+			wantBody:   `{"error":"invalid page token"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveSentMessages(gomock.Any(), defaultPageLimit, "broken").Return(nil, ErrInvalidPageToken)
 			},
 		},
 		{
@@ -62,7 +87,7 @@ func TestHandler_RetrieveSentMessages(t *testing.T) {
 			wantStatus: fiber.StatusNotFound,
 			wantBody:   `Not Found`,
 			beforeSuite: func() {
-				mockService.EXPECT().RetrieveSentMessages().Return(nil, ErrDocumentNotFound)
+				mockService.EXPECT().RetrieveSentMessages(gomock.Any(), defaultPageLimit, "").Return(nil, ErrDocumentNotFound)
 			},
 		},
 		{
@@ -71,7 +96,7 @@ func TestHandler_RetrieveSentMessages(t *testing.T) {
 			wantStatus: fiber.StatusInternalServerError,
 			wantBody:   `Internal Server Error`,
 			beforeSuite: func() {
-				mockService.EXPECT().RetrieveSentMessages().Return(nil, fiber.ErrInternalServerError)
+				mockService.EXPECT().RetrieveSentMessages(gomock.Any(), defaultPageLimit, "").Return(nil, fiber.ErrInternalServerError)
 			},
 		},
 	}
@@ -91,3 +116,875 @@ func TestHandler_RetrieveSentMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_CreateMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	messagesPath := "/messages"
+	createdID := primitive.NewObjectID()
+	validRequest := CreateMessageRequest{Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024"}
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		idempotencyKey string
+		wantStatus     int
+		wantBody       string
+		beforeSuite    func()
+	}{
+		{
+			name:        "should create message with status 201",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusCreated,
+			wantBody:    fmt.Sprintf(`{"id":"%s"}`, createdID.Hex()),
+			beforeSuite: func() {
+				mockService.EXPECT().CreateMessage(gomock.Any(), validRequest).Return(createdID, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when validation fails",
+			requestBody: CreateMessageRequest{Content: "", RecipientPhoneNumber: "+15553579024"},
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"validation failed: content is required"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, fmt.Errorf("%w: content is required", ErrValidationFailed))
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 500 when service fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusInternalServerError,
+			wantBody:    "",
+			beforeSuite: func() {
+				mockService.EXPECT().CreateMessage(gomock.Any(), validRequest).Return(primitive.NilObjectID, ErrInternalServerError)
+			},
+		},
+		{
+			name:           "should pass idempotency key to service and return original response",
+			requestBody:    validRequest,
+			idempotencyKey: "order-42",
+			wantStatus:     fiber.StatusCreated,
+			wantBody:       fmt.Sprintf(`{"id":"%s"}`, createdID.Hex()),
+			beforeSuite: func() {
+				idempotentRequest := validRequest
+				idempotentRequest.IdempotencyKey = "order-42"
+				mockService.EXPECT().CreateMessage(gomock.Any(), idempotentRequest).Return(createdID, nil)
+			},
+		},
+		{
+			name:           "should return error with status 422 when idempotency key is reused",
+			requestBody:    validRequest,
+			idempotencyKey: "order-42",
+			wantStatus:     fiber.StatusUnprocessableEntity,
+			wantBody:       `{"error":"idempotency key was already used with a different request"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, ErrIdempotencyKeyReused)
+			},
+		},
+		{
+			name:           "should return error with status 409 when idempotent request is in progress",
+			requestBody:    validRequest,
+			idempotencyKey: "order-42",
+			wantStatus:     fiber.StatusConflict,
+			wantBody:       `{"error":"a request with this idempotency key is still in progress"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, ErrIdempotencyKeyInProgress)
+			},
+		},
+		{
+			name:           "should return error with status 400 when idempotency key is too long",
+			requestBody:    validRequest,
+			idempotencyKey: strings.Repeat("k", 256),
+			wantStatus:     fiber.StatusBadRequest,
+			wantBody:       `{"error":"Invalid Idempotency-Key header"}`,
+			beforeSuite:    func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, messagesPath, reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.idempotencyKey != "" {
+				req.Header.Set("Idempotency-Key", tt.idempotencyKey)
+			}
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestHandler_RetrieveFailedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	failedPage := &FailedMessagesResponse{
+		Messages: []Message{},
+		Page:     2,
+		Limit:    10,
+		Total:    12,
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:       "should return requested page with status 200",
+			url:        "/messages/failed?page=2&limit=10",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"messages":[],"page":2,"limit":10,"total":12}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveFailedMessages(gomock.Any(), 2, 10).Return(failedPage, nil)
+			},
+		},
+		{
+			name:       "should use default pagination",
+			url:        "/messages/failed",
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveFailedMessages(gomock.Any(), 1, defaultPageLimit).Return(&FailedMessagesResponse{Messages: []Message{}, Page: 1, Limit: defaultPageLimit}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when limit is too large",
+			url:         "/messages/failed?limit=1000",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid pagination parameters"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:       "should return error with status 500 when service fails",
+			url:        "/messages/failed",
+			wantStatus: fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveFailedMessages(gomock.Any(), 1, defaultPageLimit).Return(nil, ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestHandler_RetryMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	messageID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should requeue failed message with status 204",
+			url:        "/messages/" + messageID.Hex() + "/retry",
+			wantStatus: fiber.StatusNoContent,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when failed message is not found",
+			url:        "/messages/" + messageID.Hex() + "/retry",
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(ErrDocumentNotFound)
+			},
+		},
+		{
+			name:       "should return error with status 409 when message belongs to a paused or cancelled campaign",
+			url:        "/messages/" + messageID.Hex() + "/retry",
+			wantStatus: fiber.StatusConflict,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(ErrMessageHeld)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/messages/not-an-id/retry",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodPost, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandler_RequeueStuckMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	messageID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should requeue stuck message with status 204",
+			url:        "/messages/" + messageID.Hex() + "/requeue-stuck",
+			wantStatus: fiber.StatusNoContent,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueStuckMessage(gomock.Any(), messageID).Return(nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when stuck message is not found",
+			url:        "/messages/" + messageID.Hex() + "/requeue-stuck",
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueStuckMessage(gomock.Any(), messageID).Return(ErrDocumentNotFound)
+			},
+		},
+		{
+			name:       "should return error with status 409 when message belongs to a paused or cancelled campaign",
+			url:        "/messages/" + messageID.Hex() + "/requeue-stuck",
+			wantStatus: fiber.StatusConflict,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueStuckMessage(gomock.Any(), messageID).Return(ErrMessageHeld)
+			},
+		},
+		{
+			name:       "should return error with status 500 when service fails",
+			url:        "/messages/" + messageID.Hex() + "/requeue-stuck",
+			wantStatus: fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueStuckMessage(gomock.Any(), messageID).Return(ErrInternalServerError)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/messages/not-an-id/requeue-stuck",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodPost, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandler_RequeueFailedMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	requeuePath := "/messages/failed/requeue"
+	filter := RequeueFailedMessagesRequest{FailureClass: FailureClassRetryable}
+
+	tests := []struct {
+		name        string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should requeue matching messages with status 200",
+			requestBody: filter,
+			wantStatus:  fiber.StatusOK,
+			wantBody:    `{"requeued":3}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueFailedMessages(gomock.Any(), filter).Return(int64(3), nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 500 when service fails",
+			requestBody: filter,
+			wantStatus:  fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().RequeueFailedMessages(gomock.Any(), filter).Return(int64(0), ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, requeuePath, reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestHandler_ListMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	createdAfter := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:       "should pass filters to service with status 200",
+			url:        "/messages?status=sent&recipient=%2B15553579024&created_after=2025-05-01T00:00:00Z&limit=10",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"messages":[],"next_page_token":"next"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ListMessages(gomock.Any(), MessageFilter{
+					Status:               StatusSent,
+					RecipientPhoneNumber: "+15553579024",
+					CreatedAfter:         &createdAfter,
+					Limit:                10,
+				}).Return(&MessagePage{Messages: []Message{}, NextPageToken: "next"}, nil)
+			},
+		},
+		{
+			name:       "should pass external ID and tag filters to service with status 200",
+			url:        "/messages?external_id=order-42&tag=shipping",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"messages":[]}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ListMessages(gomock.Any(), MessageFilter{
+					ExternalID: "order-42",
+					Tag:        "shipping",
+					Limit:      defaultPageLimit,
+				}).Return(&MessagePage{Messages: []Message{}}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid time filter",
+			url:         "/messages?sent_before=yesterday",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"invalid sent_before, expected RFC3339 time"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 400 for invalid limit",
+			url:         "/messages?limit=0",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid pagination parameters"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:       "should return error with status 500 when service fails",
+			url:        "/messages",
+			wantStatus: fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().ListMessages(gomock.Any(), MessageFilter{Limit: defaultPageLimit}).Return(nil, ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestHandler_RetrieveMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	messageID := primitive.NewObjectID()
+	message := &Message{
+		ID:                   messageID,
+		Content:              "Your verification code is: 729384",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusSent,
+		History: []DeliveryAttempt{
+			{WorkerID: "worker-1", StatusCode: 503, LatencyMs: 120, Error: "failed to post message, status code: 503"},
+			{WorkerID: "worker-2", StatusCode: 202, LatencyMs: 80, ProviderMessageID: "webhook-message-id"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		wantBody    *Message
+		beforeSuite func()
+	}{
+		{
+			name:       "should return message with delivery history with status 200",
+			url:        "/messages/" + messageID.Hex(),
+			wantStatus: fiber.StatusOK,
+			wantBody:   message,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(message, nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when message is not found",
+			url:        "/messages/" + messageID.Hex(),
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(nil, ErrDocumentNotFound)
+			},
+		},
+		{
+			name:       "should return error with status 500 when service fails",
+			url:        "/messages/" + messageID.Hex(),
+			wantStatus: fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(nil, ErrInternalServerError)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/messages/not-an-id",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantBody != nil {
+				var got Message
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				assert.Equal(t, *tt.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	messageID := primitive.NewObjectID()
+	content := "Your verification code is: 118274"
+	validRequest := UpdateMessageRequest{Content: &content}
+
+	tests := []struct {
+		name        string
+		url         string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should update message with status 200",
+			url:         "/messages/" + messageID.Hex(),
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().UpdateMessage(gomock.Any(), messageID, validRequest).Return(&Message{ID: messageID, Content: content, Status: StatusUnsent}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when validation fails",
+			url:         "/messages/" + messageID.Hex(),
+			requestBody: UpdateMessageRequest{},
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"validation failed: no fields to update"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().UpdateMessage(gomock.Any(), messageID, UpdateMessageRequest{}).Return(nil, fmt.Errorf("%w: no fields to update", ErrValidationFailed))
+			},
+		},
+		{
+			name:        "should return error with status 404 when message is not found",
+			url:         "/messages/" + messageID.Hex(),
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().UpdateMessage(gomock.Any(), messageID, validRequest).Return(nil, ErrDocumentNotFound)
+			},
+		},
+		{
+			name:        "should return error with status 409 when message is no longer unsent",
+			url:         "/messages/" + messageID.Hex(),
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusConflict,
+			wantBody:    `{"error":"message is no longer unsent"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().UpdateMessage(gomock.Any(), messageID, validRequest).Return(nil, ErrMessageNotEditable)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			url:         "/messages/" + messageID.Hex(),
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/messages/not-an-id",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPatch, tt.url, reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestHandler_CancelMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	messageID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should cancel message with status 204",
+			url:        "/messages/" + messageID.Hex(),
+			wantStatus: fiber.StatusNoContent,
+			beforeSuite: func() {
+				mockService.EXPECT().CancelMessage(gomock.Any(), messageID).Return(nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when message is not found",
+			url:        "/messages/" + messageID.Hex(),
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().CancelMessage(gomock.Any(), messageID).Return(ErrDocumentNotFound)
+			},
+		},
+		{
+			name:       "should return error with status 409 when message is no longer unsent",
+			url:        "/messages/" + messageID.Hex(),
+			wantStatus: fiber.StatusConflict,
+			beforeSuite: func() {
+				mockService.EXPECT().CancelMessage(gomock.Any(), messageID).Return(ErrMessageNotEditable)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/messages/not-an-id",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodDelete, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestHandler_ImportMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	body := `{"content":"Hello","recipient_phone_number":"+15553579024"}` + "\n"
+	report := &BulkImportResponse{Total: 1, Inserted: 1, Errors: []BulkRowError{}}
+
+	tests := []struct {
+		name        string
+		contentType string
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should import NDJSON upload with status 200",
+			contentType: "application/x-ndjson",
+			wantStatus:  fiber.StatusOK,
+			wantBody:    `{"total":1,"inserted":1,"errors":[]}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ImportMessages(gomock.Any(), BulkFormatNDJSON, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, r io.Reader) (*BulkImportResponse, error) {
+					got, err := io.ReadAll(r)
+					assert.NoError(t, err)
+					assert.Equal(t, body, string(got))
+					return report, nil
+				})
+			},
+		},
+		{
+			name:        "should import CSV upload",
+			contentType: "text/csv; charset=utf-8",
+			wantStatus:  fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().ImportMessages(gomock.Any(), BulkFormatCSV, gomock.Any()).Return(report, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when upload is invalid",
+			contentType: "text/csv",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"invalid bulk upload: CSV header is missing the content column"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ImportMessages(gomock.Any(), BulkFormatCSV, gomock.Any()).Return(nil, fmt.Errorf("%w: CSV header is missing the content column", ErrInvalidBulkUpload))
+			},
+		},
+		{
+			name:        "should return error with status 415 for unsupported content type",
+			contentType: "application/xml",
+			wantStatus:  fiber.StatusUnsupportedMediaType,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 500 when service fails",
+			contentType: "application/x-ndjson",
+			wantStatus:  fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().ImportMessages(gomock.Any(), BulkFormatNDJSON, gomock.Any()).Return(nil, ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodPost, "/messages/bulk", strings.NewReader(body))
+			req.Header.Set("Content-Type", tt.contentType)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestHandler_ValidateMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockMessageService(ctrl)
+	handler := NewMessageHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	validatePath := "/messages/validate"
+	validRequest := CreateMessageRequest{Content: "See http://wiki.internal", RecipientPhoneNumber: "+15553579024"}
+
+	tests := []struct {
+		name        string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should return the verdict with status 200",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusOK,
+			wantBody:    `{"valid":false,"reason":"content matched filter rule internal-url","rule":"internal-url","recipient_phone_number":"+15553579024","encoding":"GSM-7","segments":1}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ValidateMessage(validRequest).Return(&MessageVerdict{
+					Reason:               "content matched filter rule internal-url",
+					Rule:                 "internal-url",
+					RecipientPhoneNumber: "+15553579024",
+					Encoding:             EncodingGSM7,
+					Segments:             1,
+				})
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, validatePath, reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IdempotencyConfig struct {
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// IdempotencyRecord remembers which message was created for an Idempotency-Key.
+// The key is the document ID, so Mongo's unique _id index guarantees a key is
+// reserved by exactly one request.
+type IdempotencyRecord struct {
+	Key         string             `bson:"_id"`
+	RequestHash string             `bson:"request_hash"`
+	MessageID   primitive.ObjectID `bson:"message_id"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	ExpiresAt   time.Time          `bson:"expires_at"`
+}
+
+type IdempotencyRepositoryImpl struct {
+	idempotencyCollection *mongo.Collection
+}
+
+func NewIdempotencyRepositoryImpl(collection *mongo.Collection) *IdempotencyRepositoryImpl {
+	return &IdempotencyRepositoryImpl{
+		idempotencyCollection: collection,
+	}
+}
+
+// EnsureIndexes creates the TTL index that drops keys once their retention
+// window has passed.
+func (ir *IdempotencyRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := ir.idempotencyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+
+	return err
+}
+
+// ReserveKey stores the record if its key is unused. When the key is already
+// taken the existing record is returned instead and nothing is written.
+func (ir *IdempotencyRepositoryImpl) ReserveKey(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error) {
+	existing, err := ir.reserveKey(ctx, record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// The record holding the key expired or was released between the insert
+		// and the lookup, so the key is free again.
+		existing, err = ir.reserveKey(ctx, record)
+	}
+
+	return existing, err
+}
+
+func (ir *IdempotencyRepositoryImpl) reserveKey(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error) {
+	_, err := ir.idempotencyCollection.InsertOne(ctx, record)
+	if err == nil {
+		return nil, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	var existing IdempotencyRecord
+	if err := ir.idempotencyCollection.FindOne(ctx, bson.M{"_id": record.Key}).Decode(&existing); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+
+	return &existing, nil
+}
+
+// ReleaseKey removes a reservation whose request failed, so the client can
+// retry with the same key.
+func (ir *IdempotencyRepositoryImpl) ReleaseKey(ctx context.Context, key string) error {
+	_, err := ir.idempotencyCollection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testIdempotencyCollection = "idempotency_keys"
+
+func TestIdempotencyRepository_ReserveKey(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	idempotencyRepository := NewIdempotencyRepositoryImpl(client.Database(testDB).Collection(testIdempotencyCollection))
+	assert.NoError(t, idempotencyRepository.EnsureIndexes(context.Background()))
+
+	now := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	record := &IdempotencyRecord{
+		Key:         "order-42",
+		RequestHash: "hash",
+		MessageID:   primitive.NewObjectID(),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(24 * time.Hour),
+	}
+
+	existing, err := idempotencyRepository.ReserveKey(context.Background(), record)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	repeated := *record
+	repeated.MessageID = primitive.NewObjectID()
+	existing, err = idempotencyRepository.ReserveKey(context.Background(), &repeated)
+	assert.NoError(t, err)
+	assert.Equal(t, record, existing)
+
+	assert.NoError(t, idempotencyRepository.ReleaseKey(context.Background(), record.Key))
+
+	existing, err = idempotencyRepository.ReserveKey(context.Background(), &repeated)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+}
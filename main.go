@@ -44,6 +44,7 @@ func main() {
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
+		StreamRequestBody:     true,
 	})
 
 	app.Get("/swagger/*", swagger.New(swagger.Config{
@@ -76,15 +77,44 @@ func main() {
 
 	messagesCollection := messagesMongoClient.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("MESSAGES_COLLECTION_NAME"))
 
+	validate := validator.New()
+	if err := RegisterPhoneValidation(validate, config.Phone.DefaultRegion); err != nil {
+		logger.Fatal("Failed to register phone number validation", zap.Error(err))
+	}
+	if err := RegisterSegmentValidation(validate, config.SMS.MaxSegments); err != nil {
+		logger.Fatal("Failed to register segment validation", zap.Error(err))
+	}
+
+	contentFilter, err := NewContentFilter(config.ContentFilter)
+	if err != nil {
+		logger.Fatal("Failed to load content filter rules", zap.Error(err))
+	}
+
+	err = WatchConfig(os.Getenv("CONFIG_PATH"), os.Getenv("CONFIG_ENV"), func(changed *Config, err error) {
+		if err == nil {
+			err = contentFilter.Reload(changed.ContentFilter)
+		}
+		if err != nil {
+			logger.Error("Failed to reload content filter rules, keeping the current ones", zap.Error(err))
+			return
+		}
+		logger.Info("Content filter rules reloaded", zap.Int("rules", len(changed.ContentFilter.Rules)))
+	})
+	if err != nil {
+		logger.Fatal("Failed to watch config", zap.Error(err))
+	}
+
 	messagesRepository := NewMessageRepositoryImpl(messagesCollection)
-	messageService := NewMessageServiceImpl(messagesRepository)
-	messageHandler := NewMessageHandler(messageService)
-	messageHandler.RegisterRoutes(app)
+	if err := messagesRepository.EnsureIndexes(ctx); err != nil {
+		logger.Fatal("Failed to create message indexes", zap.Error(err))
+	}
 
-	webhookHttpClient := http.Client{
-		Timeout: config.WebhookClient.Timeout,
+	idempotencyCollection := messagesMongoClient.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("IDEMPOTENCY_COLLECTION_NAME"))
+
+	idempotencyRepository := NewIdempotencyRepositoryImpl(idempotencyCollection)
+	if err := idempotencyRepository.EnsureIndexes(ctx); err != nil {
+		logger.Fatal("Failed to create idempotency key indexes", zap.Error(err))
 	}
-	webhookClient := client.NewWebhookClient(config.WebhookClient.Host, &webhookHttpClient, &config.WebhookClient)
 
 	redisDB, err := strconv.Atoi(os.Getenv("REDIS_DB"))
 	if err != nil {
@@ -93,17 +123,68 @@ func main() {
 
 	messageCache := NewRedisCache(os.Getenv("REDIS_URI"), os.Getenv("REDIS_PASSWORD"), redisDB, config.Cache)
 
-	validate := validator.New()
+	messageService := NewMessageServiceImpl(messagesRepository, idempotencyRepository, contentFilter, messageCache, *config, validate)
+	messageHandler := NewMessageHandler(messageService)
+	messageHandler.RegisterRoutes(app)
+
+	recurringCollection := messagesMongoClient.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("RECURRING_COLLECTION_NAME"))
+
+	recurringRepository := NewRecurringScheduleRepositoryImpl(recurringCollection)
+	if err := recurringRepository.EnsureIndexes(ctx); err != nil {
+		logger.Fatal("Failed to create recurring schedule indexes", zap.Error(err))
+	}
+	recurringService := NewRecurringScheduleServiceImpl(recurringRepository, *config, validate)
+	recurringHandler := NewRecurringScheduleHandler(recurringService)
+	recurringHandler.RegisterRoutes(app)
+
+	templateCollection := messagesMongoClient.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("TEMPLATES_COLLECTION_NAME"))
+
+	templateRepository := NewTemplateRepositoryImpl(templateCollection)
+	if err := templateRepository.EnsureIndexes(ctx); err != nil {
+		logger.Fatal("Failed to create template indexes", zap.Error(err))
+	}
+	templateService := NewTemplateServiceImpl(templateRepository, validate)
+	templateHandler := NewTemplateHandler(templateService)
+	templateHandler.RegisterRoutes(app)
+
+	campaignCollection := messagesMongoClient.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("CAMPAIGNS_COLLECTION_NAME"))
+
+	campaignRepository := NewCampaignRepositoryImpl(campaignCollection)
+	campaignService := NewCampaignServiceImpl(campaignRepository, messagesRepository, *config, validate)
+	campaignHandler := NewCampaignHandler(campaignService)
+	campaignHandler.RegisterRoutes(app)
+
+	webhookHttpClient := http.Client{
+		Timeout: config.WebhookClient.Timeout,
+	}
+	webhookClient := client.NewWebhookClient(config.WebhookClient.Host, &webhookHttpClient, &config.WebhookClient)
+
+	suppressionCollection := messagesMongoClient.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("SUPPRESSIONS_COLLECTION_NAME"))
+
+	suppressionRepository := NewSuppressionRepositoryImpl(suppressionCollection)
+	if err := suppressionRepository.EnsureIndexes(ctx); err != nil {
+		logger.Fatal("Failed to create suppression indexes", zap.Error(err))
+	}
+	suppressionService := NewSuppressionServiceImpl(suppressionRepository, messageCache, *config, validate)
+	suppressionHandler := NewSuppressionHandler(suppressionService)
+	suppressionHandler.RegisterRoutes(app)
+
+	statsService := NewStatsServiceImpl(messagesRepository, messageCache, *config)
+	statsHandler := NewStatsHandler(statsService)
+	statsHandler.RegisterRoutes(app)
 
 	rateLimiter := NewRateLimiter(config.RateLimiter, logger)
 
 	poolWg := &sync.WaitGroup{}
-	pool := NewWorkerPool(config.Pool.NumWorkers, messagesRepository, webhookClient, messageCache, *config, logger, poolWg, config.Pool.InitialJobFetch, validate, rateLimiter)
+	pool := NewWorkerPool(config.Pool.NumWorkers, messagesRepository, templateRepository, suppressionService, contentFilter, webhookClient, messageCache, *config, logger, poolWg, config.Pool.InitialJobFetch, validate, rateLimiter)
 	pool.Start()
 
 	workerPoolHandler := NewWorkerPoolHandler(pool)
 	workerPoolHandler.RegisterRoutes(app)
 
+	recurringScheduler := NewRecurringScheduler(recurringRepository, messagesRepository, config.Recurring, logger)
+	recurringScheduler.Start()
+
 	serverShutdown := make(chan struct{})
 	go func() {
 		logger.Info("Starting server", zap.String("port", os.Getenv("PORT")))
@@ -126,6 +207,10 @@ func main() {
 	<-serverShutdown
 	logger.Info("HTTP Server shutdown complete")
 
+	logger.Info("Stopping recurring scheduler...")
+	recurringScheduler.Stop()
+	logger.Info("Recurring scheduler stopped")
+
 	logger.Info("Shutting down worker pool...")
 	workerShutdownCtx, workerCancel := context.WithTimeout(context.Background(), config.Pool.Timeout)
 	defer workerCancel()
@@ -159,11 +244,24 @@ func seedMongoDB(ctx context.Context, client *mongo.Client) error {
 	}
 	defer jsonFile.Close()
 
-	var seedData []interface{}
-	if err := json.NewDecoder(jsonFile).Decode(&seedData); err != nil {
+	// Decoding into Message stores the timestamps as BSON dates rather than the
+	// strings they are in the file.
+	var messages []Message
+	if err := json.NewDecoder(jsonFile).Decode(&messages); err != nil {
 		return err
 	}
 
+	seedData := make([]interface{}, len(messages))
+	for i := range messages {
+		message := &messages[i]
+		message.PriorityRank = priorityRank(message.Priority)
+		if message.ScheduledAt.IsZero() {
+			message.ScheduledAt = message.CreatedAt
+		}
+		message.Encoding, message.Segments = MeasureSegments(message.Content)
+		seedData[i] = message
+	}
+
 	collection := client.Database(os.Getenv("MESSAGES_DB_NAME")).Collection(os.Getenv("MESSAGES_COLLECTION_NAME"))
 	if _, err := collection.InsertMany(ctx, seedData); err != nil {
 		return err
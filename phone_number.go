@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/nyaruka/phonenumbers"
+)
+
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+type PhoneConfig struct {
+	DefaultRegion string `mapstructure:"defaultRegion"` // ISO 3166-1 alpha-2 region of numbers without a country code
+}
+
+// NormalizePhoneNumber returns the E.164 form (e.g. +905321234567) of a phone
+// number. International numbers start with "+" or "00"; any other number is
+// read as a national number of defaultRegion. Spaces, dashes, dots, slashes
+// and parentheses are ignored. Numbering plans come from libphonenumber, and a
+// number is accepted when its length is possible for its country.
+func NormalizePhoneNumber(raw, defaultRegion string) (string, error) {
+	number := strings.TrimSpace(raw)
+	if strings.HasPrefix(number, "00") {
+		number = "+" + number[2:]
+	}
+
+	for i, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+		case r == '+' && i == 0:
+		case strings.ContainsRune(" -./()", r):
+		default:
+			return "", fmt.Errorf("%w: unexpected character %q", ErrInvalidPhoneNumber, r)
+		}
+	}
+
+	if !strings.HasPrefix(number, "+") && phonenumbers.GetCountryCodeForRegion(defaultRegion) == 0 {
+		return "", fmt.Errorf("%w: national number without a default region", ErrInvalidPhoneNumber)
+	}
+
+	parsed, err := phonenumbers.Parse(number, defaultRegion)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPhoneNumber, err.Error())
+	}
+
+	if !phonenumbers.IsPossibleNumber(parsed) {
+		return "", fmt.Errorf("%w: wrong number of digits for country calling code %d", ErrInvalidPhoneNumber, parsed.GetCountryCode())
+	}
+
+	return phonenumbers.Format(parsed, phonenumbers.E164), nil
+}
+
+// normalizePhoneNumberField replaces a phone number that passed the "phone"
+// validation with its E.164 form.
+func normalizePhoneNumberField(phoneNumber *string, defaultRegion string) error {
+	normalized, err := NormalizePhoneNumber(*phoneNumber, defaultRegion)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+
+	*phoneNumber = normalized
+	return nil
+}
+
+// RegisterPhoneValidation registers the "phone" validation tag. A field passes
+// when it normalizes to a possible E.164 number, national numbers being read in
+// defaultRegion. The field itself is left as is; callers store the result of
+// NormalizePhoneNumber so every store keys on the same value.
+func RegisterPhoneValidation(validate *validator.Validate, defaultRegion string) error {
+	if defaultRegion != "" && phonenumbers.GetCountryCodeForRegion(defaultRegion) == 0 {
+		return fmt.Errorf("unsupported default phone region %q", defaultRegion)
+	}
+
+	return validate.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		_, err := NormalizePhoneNumber(fl.Field().String(), defaultRegion)
+		return err == nil
+	})
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPhoneRegion is the default phone region of the services under test.
+const testPhoneRegion = "TR"
+
+// newTestValidator returns a validator with the custom tags main registers.
+func newTestValidator() *validator.Validate {
+	validate := validator.New()
+	if err := RegisterPhoneValidation(validate, testPhoneRegion); err != nil {
+		panic(err)
+	}
+	// A single segment keeps plain GSM-7 content at 160 characters.
+	if err := RegisterSegmentValidation(validate, 1); err != nil {
+		panic(err)
+	}
+	return validate
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		defaultRegion string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name: "should keep a number already in E.164",
+			raw:  "+905321234567",
+			want: "+905321234567",
+		},
+		{
+			name: "should strip formatting characters",
+			raw:  " +1 (202) 555-0123 ",
+			want: "+12025550123",
+		},
+		{
+			name: "should read the 00 international prefix",
+			raw:  "0044 20 7946 0958",
+			want: "+442079460958",
+		},
+		{
+			name:          "should drop the trunk prefix of a national number",
+			raw:           "0532 123 45 67",
+			defaultRegion: "TR",
+			want:          "+905321234567",
+		},
+		{
+			name:          "should read a national number without trunk prefix",
+			raw:           "202.555.0123",
+			defaultRegion: "US",
+			want:          "+12025550123",
+		},
+		{
+			name:          "should drop the NANP trunk prefix",
+			raw:           "1-202-555-0123",
+			defaultRegion: "US",
+			want:          "+12025550123",
+		},
+		{
+			name: "should accept numbers of calling codes without a numbering plan",
+			raw:  "+37060012345",
+			want: "+37060012345",
+		},
+		{
+			name:    "should reject a national number without a default region",
+			raw:     "05321234567",
+			wantErr: true,
+		},
+		{
+			name:    "should reject letters",
+			raw:     "+90532ABC4567",
+			wantErr: true,
+		},
+		{
+			name:    "should reject a calling code that is not assigned",
+			raw:     "+2801234567",
+			wantErr: true,
+		},
+		{
+			name:    "should reject a number with too few digits for its calling code",
+			raw:     "+1234567890",
+			wantErr: true,
+		},
+		{
+			name:    "should reject a number longer than E.164 allows",
+			raw:     "+3712345678901234",
+			wantErr: true,
+		},
+		{
+			name: "should drop a trunk prefix written after the country code",
+			raw:  "+90 (0532) 123 45 67",
+			want: "+905321234567",
+		},
+		{
+			name:          "should reject a national number too short for the default region",
+			raw:           "0532",
+			defaultRegion: "TR",
+			wantErr:       true,
+		},
+		{
+			name:          "should reject a default region that does not exist",
+			raw:           "0532 123 45 67",
+			defaultRegion: "XX",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizePhoneNumber(tt.raw, tt.defaultRegion)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPhoneNumber)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegisterPhoneValidation(t *testing.T) {
+	assert.Error(t, RegisterPhoneValidation(validator.New(), "XX"))
+
+	validate := newTestValidator()
+
+	// The validator only checks the number; normalizing it is up to the caller.
+	message := &Message{Content: "Hello", RecipientPhoneNumber: "0532 123 45 67"}
+	assert.NoError(t, validate.StructPartial(message, "RecipientPhoneNumber"))
+	assert.Equal(t, "0532 123 45 67", message.RecipientPhoneNumber)
+
+	assert.Error(t, validate.Var("12345", "phone"))
+}
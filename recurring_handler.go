@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecurringScheduleService interface {
+	CreateSchedule(ctx context.Context, req CreateRecurringScheduleRequest) (primitive.ObjectID, error)
+	RetrieveSchedules(ctx context.Context) ([]RecurringSchedule, error)
+	PauseSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error)
+	ResumeSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error)
+	DeleteSchedule(ctx context.Context, scheduleID primitive.ObjectID) error
+}
+
+type RecurringSchedule struct {
+	ID                   primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CronExpression       string             `bson:"cron_expression" json:"cron_expression" validate:"required"`
+	Timezone             string             `bson:"timezone" json:"timezone" validate:"required,timezone"`
+	Content              string             `bson:"content" json:"content" validate:"min=1,segments"`
+	RecipientPhoneNumber string             `bson:"recipient_phone_number" json:"recipient_phone_number" validate:"required,phone"`
+	Status               string             `bson:"status" json:"status"`
+	NextRunAt            time.Time          `bson:"next_run_at" json:"next_run_at"`
+	LastRunAt            time.Time          `bson:"last_run_at" json:"last_run_at"`
+	CreatedAt            time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type CreateRecurringScheduleRequest struct {
+	CronExpression       string `json:"cron_expression"` // standard five-field cron expression, e.g. "0 9 * * 1"
+	Timezone             string `json:"timezone"`        // IANA timezone, e.g. "Europe/Istanbul"
+	Content              string `json:"content"`
+	RecipientPhoneNumber string `json:"recipient_phone_number"`
+}
+
+type CreateRecurringScheduleResponse struct {
+	ID string `json:"id"`
+}
+
+type RecurringScheduleActionRequest struct {
+	Action string `json:"action"` // "pause" or "resume"
+}
+
+type RecurringScheduleHandler struct {
+	scheduleService RecurringScheduleService
+}
+
+func NewRecurringScheduleHandler(rs RecurringScheduleService) *RecurringScheduleHandler {
+	return &RecurringScheduleHandler{
+		scheduleService: rs,
+	}
+}
+
+func (h *RecurringScheduleHandler) RegisterRoutes(app *fiber.App) {
+	recurringGroup := app.Group("/recurring-messages")
+	recurringGroup.Post("/", h.CreateSchedule)
+	recurringGroup.Get("/", h.RetrieveSchedules)
+	recurringGroup.Put("/:id/state", h.ControlSchedule)
+	recurringGroup.Delete("/:id", h.DeleteSchedule)
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring message
+// @Description Create a cron driven schedule that enqueues a message on every occurrence
+// @Tags recurring-messages
+// @Accept json
+// @Produce json
+// @Param schedule body CreateRecurringScheduleRequest true "Recurring schedule to create"
+// @Success 201 {object} CreateRecurringScheduleResponse
+// @Failure 400 {object} map[string]string "Invalid request body or validation error"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /recurring-messages [post]
+func (h *RecurringScheduleHandler) CreateSchedule(c *fiber.Ctx) error {
+	var req CreateRecurringScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	id, err := h.scheduleService.CreateSchedule(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CreateRecurringScheduleResponse{
+		ID: id.Hex(),
+	})
+}
+
+// RetrieveSchedules godoc
+// @Summary Retrieve recurring messages
+// @Description Get all recurring message schedules
+// @Tags recurring-messages
+// @Accept json
+// @Produce json
+// @Success 200 {array} RecurringSchedule
+// @Failure 404 {object} nil "No recurring messages found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /recurring-messages [get]
+func (h *RecurringScheduleHandler) RetrieveSchedules(c *fiber.Ctx) error {
+	schedules, err := h.scheduleService.RetrieveSchedules(c.UserContext())
+	if err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(schedules)
+}
+
+// ControlSchedule godoc
+// @Summary Updates a recurring message state
+// @Description Pause or resume a recurring message schedule
+// @Tags recurring-messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Recurring schedule ID"
+// @Param action body RecurringScheduleActionRequest true "Action to perform `pause` or `resume`"
+// @Success 200 {object} RecurringSchedule
+// @Failure 400 {object} map[string]string "Invalid ID or action"
+// @Failure 404 {object} nil "Recurring message not found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /recurring-messages/{id}/state [put]
+func (h *RecurringScheduleHandler) ControlSchedule(c *fiber.Ctx) error {
+	scheduleID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidScheduleID.Error(),
+		})
+	}
+
+	var req RecurringScheduleActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	var schedule *RecurringSchedule
+	switch req.Action {
+	case "pause":
+		schedule, err = h.scheduleService.PauseSchedule(c.UserContext(), scheduleID)
+	case "resume":
+		schedule, err = h.scheduleService.ResumeSchedule(c.UserContext(), scheduleID)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid action. Use 'pause' or 'resume'",
+		})
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a recurring message
+// @Description Delete a recurring message schedule. Messages already enqueued are not affected.
+// @Tags recurring-messages
+// @Param id path string true "Recurring schedule ID"
+// @Success 204 {object} nil "Deleted"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} nil "Recurring message not found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /recurring-messages/{id} [delete]
+func (h *RecurringScheduleHandler) DeleteSchedule(c *fiber.Ctx) error {
+	scheduleID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidScheduleID.Error(),
+		})
+	}
+
+	if err := h.scheduleService.DeleteSchedule(c.UserContext(), scheduleID); err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
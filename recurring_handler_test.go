@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestRecurringHandler_CreateSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockRecurringScheduleService(ctrl)
+	handler := NewRecurringScheduleHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	recurringPath := "/recurring-messages"
+	createdID := primitive.NewObjectID()
+	validRequest := CreateRecurringScheduleRequest{
+		CronExpression:       "0 9 * * 1",
+		Timezone:             "Europe/Istanbul",
+		Content:              "Weekly reminder",
+		RecipientPhoneNumber: "+15553579024",
+	}
+
+	tests := []struct {
+		name        string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should create recurring message with status 201",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusCreated,
+			wantBody:    fmt.Sprintf(`{"id":"%s"}`, createdID.Hex()),
+			beforeSuite: func() {
+				mockService.EXPECT().CreateSchedule(gomock.Any(), validRequest).Return(createdID, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when validation fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"validation failed: invalid cron expression"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateSchedule(gomock.Any(), validRequest).Return(primitive.NilObjectID, fmt.Errorf("%w: %s", ErrValidationFailed, ErrInvalidCronExpression.Error()))
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 500 when service fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateSchedule(gomock.Any(), validRequest).Return(primitive.NilObjectID, ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, recurringPath, reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestRecurringHandler_ControlSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockRecurringScheduleService(ctrl)
+	handler := NewRecurringScheduleHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	scheduleID := primitive.NewObjectID()
+	statePath := fmt.Sprintf("/recurring-messages/%s/state", scheduleID.Hex())
+
+	tests := []struct {
+		name        string
+		url         string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should pause recurring message with status 200",
+			url:         statePath,
+			requestBody: RecurringScheduleActionRequest{Action: "pause"},
+			wantStatus:  fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().PauseSchedule(gomock.Any(), scheduleID).Return(&RecurringSchedule{ID: scheduleID, Status: RecurringStatusPaused}, nil)
+			},
+		},
+		{
+			name:        "should resume recurring message with status 200",
+			url:         statePath,
+			requestBody: RecurringScheduleActionRequest{Action: "resume"},
+			wantStatus:  fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().ResumeSchedule(gomock.Any(), scheduleID).Return(&RecurringSchedule{ID: scheduleID, Status: RecurringStatusActive}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid action",
+			url:         statePath,
+			requestBody: RecurringScheduleActionRequest{Action: "stop"},
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid action. Use 'pause' or 'resume'"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			url:         "/recurring-messages/not-an-id/state",
+			requestBody: RecurringScheduleActionRequest{Action: "pause"},
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"invalid schedule ID"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return error with status 404 when schedule is not found",
+			url:         statePath,
+			requestBody: RecurringScheduleActionRequest{Action: "pause"},
+			wantStatus:  fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().PauseSchedule(gomock.Any(), scheduleID).Return(nil, ErrDocumentNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			jsonBody, err := json.Marshal(tt.requestBody)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(fiber.MethodPut, tt.url, bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestRecurringHandler_DeleteSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockRecurringScheduleService(ctrl)
+	handler := NewRecurringScheduleHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	scheduleID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should delete recurring message with status 204",
+			wantStatus: fiber.StatusNoContent,
+			beforeSuite: func() {
+				mockService.EXPECT().DeleteSchedule(gomock.Any(), scheduleID).Return(nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when schedule is not found",
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().DeleteSchedule(gomock.Any(), scheduleID).Return(ErrDocumentNotFound)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodDelete, "/recurring-messages/"+scheduleID.Hex(), nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	RecurringStatusActive = "active"
+	RecurringStatusPaused = "paused"
+)
+
+type RecurringScheduleRepositoryImpl struct {
+	scheduleCollection *mongo.Collection
+}
+
+func NewRecurringScheduleRepositoryImpl(collection *mongo.Collection) *RecurringScheduleRepositoryImpl {
+	return &RecurringScheduleRepositoryImpl{
+		scheduleCollection: collection,
+	}
+}
+
+// EnsureIndexes creates the index used by the scheduler to find due schedules.
+func (rr *RecurringScheduleRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := rr.scheduleCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}},
+	})
+
+	return err
+}
+
+func (rr *RecurringScheduleRepositoryImpl) InsertSchedule(ctx context.Context, schedule *RecurringSchedule) (primitive.ObjectID, error) {
+	result, err := rr.scheduleCollection.InsertOne(ctx, schedule)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, ErrInvalidMessageID
+	}
+
+	return id, nil
+}
+
+func (rr *RecurringScheduleRepositoryImpl) RetrieveSchedules(ctx context.Context) ([]RecurringSchedule, error) {
+	cursor, err := rr.scheduleCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": -1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var schedules []RecurringSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, ErrDocumentDecodingFailed
+	}
+
+	return schedules, nil
+}
+
+func (rr *RecurringScheduleRepositoryImpl) RetrieveSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error) {
+	var schedule RecurringSchedule
+	err := rr.scheduleCollection.FindOne(ctx, bson.M{"_id": scheduleID}).Decode(&schedule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (rr *RecurringScheduleRepositoryImpl) UpdateScheduleStatus(ctx context.Context, scheduleID primitive.ObjectID, status string, nextRunAt time.Time) error {
+	filter := bson.M{
+		"_id": scheduleID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":      status,
+			"next_run_at": nextRunAt,
+		},
+	}
+
+	result, err := rr.scheduleCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (rr *RecurringScheduleRepositoryImpl) DeleteSchedule(ctx context.Context, scheduleID primitive.ObjectID) error {
+	result, err := rr.scheduleCollection.DeleteOne(ctx, bson.M{"_id": scheduleID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (rr *RecurringScheduleRepositoryImpl) FetchDueSchedules(ctx context.Context, now time.Time, limit int) ([]RecurringSchedule, error) {
+	filter := bson.M{
+		"status":      RecurringStatusActive,
+		"next_run_at": bson.M{"$lte": now},
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"next_run_at": 1}).
+		SetLimit(int64(limit))
+
+	cursor, err := rr.scheduleCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var schedules []RecurringSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return nil, ErrDocumentDecodingFailed
+	}
+
+	return schedules, nil
+}
+
+// AdvanceNextRun moves a schedule to its next occurrence only if its next_run_at
+// still matches the occurrence the caller read, so replicas racing on the same
+// occurrence advance the schedule only once.
+func (rr *RecurringScheduleRepositoryImpl) AdvanceNextRun(ctx context.Context, scheduleID primitive.ObjectID, currentRunAt, nextRunAt time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":         scheduleID,
+		"status":      RecurringStatusActive,
+		"next_run_at": currentRunAt,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"next_run_at": nextRunAt,
+			"last_run_at": currentRunAt,
+		},
+	}
+
+	result, err := rr.scheduleCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
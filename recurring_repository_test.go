@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const testRecurringCollection = "recurring_messages"
+
+func TestRecurringRepository_AdvanceNextRun(t *testing.T) {
+	currentRunAt := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	nextRunAt := time.Date(2025, 5, 11, 9, 0, 0, 0, time.UTC)
+
+	schedule := RecurringSchedule{
+		ID:                   primitive.NewObjectID(),
+		CronExpression:       "0 9 * * *",
+		Timezone:             "UTC",
+		Content:              "Daily reminder",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               RecurringStatusActive,
+		NextRunAt:            currentRunAt,
+	}
+
+	tests := []struct {
+		name         string
+		currentRunAt time.Time
+		wantClaimed  bool
+		beforeSuite  func() (*mongo.Client, func())
+	}{
+		{
+			name:         "should advance schedule when occurrence matches",
+			currentRunAt: currentRunAt,
+			wantClaimed:  true,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				_, err = client.Database(testDB).Collection(testRecurringCollection).InsertOne(context.Background(), schedule)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:         "should not advance schedule already advanced by another replica",
+			currentRunAt: currentRunAt.Add(-24 * time.Hour),
+			wantClaimed:  false,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				_, err = client.Database(testDB).Collection(testRecurringCollection).InsertOne(context.Background(), schedule)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, cleanFunc := tt.beforeSuite()
+
+			defer client.Disconnect(context.Background())
+			defer cleanFunc()
+
+			collection := client.Database(testDB).Collection(testRecurringCollection)
+			scheduleRepository := NewRecurringScheduleRepositoryImpl(collection)
+			claimed, err := scheduleRepository.AdvanceNextRun(context.Background(), schedule.ID, tt.currentRunAt, nextRunAt)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantClaimed, claimed)
+
+			var updatedSchedule RecurringSchedule
+			err = collection.FindOne(context.Background(), bson.M{"_id": schedule.ID}).Decode(&updatedSchedule)
+			assert.NoError(t, err)
+			if tt.wantClaimed {
+				assert.True(t, nextRunAt.Equal(updatedSchedule.NextRunAt))
+			} else {
+				assert.True(t, currentRunAt.Equal(updatedSchedule.NextRunAt))
+			}
+		})
+	}
+}
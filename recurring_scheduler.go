@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+type RecurringSchedulerConfig struct {
+	Interval  time.Duration `mapstructure:"interval"`
+	BatchSize int           `mapstructure:"batchSize"`
+}
+
+type RecurringScheduleStore interface {
+	FetchDueSchedules(ctx context.Context, now time.Time, limit int) ([]RecurringSchedule, error)
+	AdvanceNextRun(ctx context.Context, scheduleID primitive.ObjectID, currentRunAt, nextRunAt time.Time) (bool, error)
+}
+
+type RecurringMessageStore interface {
+	InsertMessage(ctx context.Context, message *Message) (primitive.ObjectID, error)
+}
+
+// RecurringScheduler periodically turns due recurring schedules into unsent
+// messages that the worker pool picks up like any other message.
+type RecurringScheduler struct {
+	scheduleStore RecurringScheduleStore
+	messageStore  RecurringMessageStore
+	config        RecurringSchedulerConfig
+	logger        *zap.Logger
+	wg            sync.WaitGroup
+	stop          chan struct{}
+	stopOnce      sync.Once
+}
+
+func NewRecurringScheduler(scheduleStore RecurringScheduleStore, messageStore RecurringMessageStore, config RecurringSchedulerConfig, logger *zap.Logger) *RecurringScheduler {
+	return &RecurringScheduler{
+		scheduleStore: scheduleStore,
+		messageStore:  messageStore,
+		config:        config,
+		logger:        logger.With(zap.String("component", "recurring-scheduler")),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler loop in the background. A non-positive interval
+// disables the scheduler, so no recurring messages are materialized.
+func (s *RecurringScheduler) Start() {
+	if s.config.Interval <= 0 {
+		s.logger.Warn("Recurring scheduler disabled: interval is not positive", zap.Duration("interval", s.config.Interval))
+		return
+	}
+
+	s.wg.Add(1)
+	go s.run()
+}
+
+func (s *RecurringScheduler) run() {
+	defer s.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Recurring scheduler started", zap.Duration("interval", s.config.Interval))
+
+	for {
+		select {
+		case <-ticker.C:
+			materialized, err := s.MaterializeDue(ctx, time.Now())
+			if err != nil {
+				s.logger.Error("Failed to materialize recurring messages", zap.Error(err))
+				continue
+			}
+			if materialized > 0 {
+				s.logger.Info("Recurring messages materialized", zap.Int("count", materialized))
+			}
+		case <-s.stop:
+			s.logger.Info("Recurring scheduler stopped")
+			return
+		}
+	}
+}
+
+// MaterializeDue enqueues one message for every schedule whose occurrence is due
+// and returns how many messages this replica created. The message is inserted
+// before next_run_at is advanced, so a failed insert leaves the occurrence due
+// for the next tick. The unique index on recurring_schedule_id and scheduled_at
+// rejects an occurrence another replica, or an earlier tick whose advance
+// failed, already enqueued; the schedule is then only advanced.
+func (s *RecurringScheduler) MaterializeDue(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.scheduleStore.FetchDueSchedules(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	materialized := 0
+	for _, schedule := range schedules {
+		nextRunAt, err := NextOccurrence(schedule.CronExpression, schedule.Timezone, now)
+		if err != nil {
+			s.logger.Error("Failed to calculate next occurrence",
+				zap.String("schedule_id", schedule.ID.Hex()),
+				zap.Error(err))
+			continue
+		}
+
+		scheduleID := schedule.ID
+		message := &Message{
+			Content:              schedule.Content,
+			RecipientPhoneNumber: schedule.RecipientPhoneNumber,
+			Status:               StatusUnsent,
+			Priority:             PriorityNormal,
+			PriorityRank:         priorityRank(PriorityNormal),
+			CreatedAt:            now,
+			ScheduledAt:          schedule.NextRunAt,
+			RecurringScheduleID:  &scheduleID,
+		}
+		message.Encoding, message.Segments = MeasureSegments(message.Content)
+
+		inserted := true
+		if _, err := s.messageStore.InsertMessage(ctx, message); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				s.logger.Error("Failed to insert recurring message",
+					zap.String("schedule_id", schedule.ID.Hex()),
+					zap.Error(err))
+				continue
+			}
+			s.logger.Debug("Occurrence already materialized", zap.String("schedule_id", schedule.ID.Hex()))
+			inserted = false
+		}
+
+		if _, err := s.scheduleStore.AdvanceNextRun(ctx, schedule.ID, schedule.NextRunAt, nextRunAt); err != nil {
+			s.logger.Error("Failed to advance recurring schedule",
+				zap.String("schedule_id", schedule.ID.Hex()),
+				zap.Error(err))
+		}
+
+		if !inserted {
+			continue
+		}
+
+		materialized++
+	}
+
+	return materialized, nil
+}
+
+func (s *RecurringScheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestRecurringScheduler_MaterializeDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockScheduleStore := NewMockRecurringScheduleStore(ctrl)
+	mockMessageStore := NewMockRecurringMessageStore(ctrl)
+	config := RecurringSchedulerConfig{
+		Interval:  time.Second,
+		BatchSize: 10,
+	}
+
+	now := time.Date(2025, 5, 10, 9, 0, 30, 0, time.UTC)
+	dueSchedule := RecurringSchedule{
+		ID:                   primitive.NewObjectID(),
+		CronExpression:       "0 9 * * *",
+		Timezone:             "UTC",
+		Content:              "Daily reminder",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               RecurringStatusActive,
+		NextRunAt:            time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC),
+	}
+	wantNextRunAt := time.Date(2025, 5, 11, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		wantMaterialized int
+		wantErr          bool
+		beforeSuite      func()
+	}{
+		{
+			name:             "should enqueue message for due schedule",
+			wantMaterialized: 1,
+			wantErr:          false,
+			beforeSuite: func() {
+				mockScheduleStore.EXPECT().FetchDueSchedules(gomock.Any(), now, config.BatchSize).Return([]RecurringSchedule{dueSchedule}, nil)
+				mockScheduleStore.EXPECT().AdvanceNextRun(gomock.Any(), dueSchedule.ID, dueSchedule.NextRunAt, wantNextRunAt).Return(true, nil)
+				mockMessageStore.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Equal(t, StatusUnsent, message.Status)
+					assert.Equal(t, dueSchedule.Content, message.Content)
+					assert.Equal(t, dueSchedule.RecipientPhoneNumber, message.RecipientPhoneNumber)
+					assert.Equal(t, dueSchedule.NextRunAt, message.ScheduledAt)
+					assert.Equal(t, dueSchedule.ID, *message.RecurringScheduleID)
+					return primitive.NewObjectID(), nil
+				})
+			},
+		},
+		{
+			name:             "should skip occurrence already materialized by another replica",
+			wantMaterialized: 0,
+			wantErr:          false,
+			beforeSuite: func() {
+				mockScheduleStore.EXPECT().FetchDueSchedules(gomock.Any(), now, config.BatchSize).Return([]RecurringSchedule{dueSchedule}, nil)
+				mockMessageStore.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}})
+				mockScheduleStore.EXPECT().AdvanceNextRun(gomock.Any(), dueSchedule.ID, dueSchedule.NextRunAt, wantNextRunAt).Return(false, nil)
+			},
+		},
+		{
+			name:             "should leave occurrence due when message cannot be inserted",
+			wantMaterialized: 0,
+			wantErr:          false,
+			beforeSuite: func() {
+				mockScheduleStore.EXPECT().FetchDueSchedules(gomock.Any(), now, config.BatchSize).Return([]RecurringSchedule{dueSchedule}, nil)
+				mockMessageStore.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, assert.AnError)
+			},
+		},
+		{
+			name:             "should count inserted message when schedule cannot be advanced",
+			wantMaterialized: 1,
+			wantErr:          false,
+			beforeSuite: func() {
+				mockScheduleStore.EXPECT().FetchDueSchedules(gomock.Any(), now, config.BatchSize).Return([]RecurringSchedule{dueSchedule}, nil)
+				mockMessageStore.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).Return(primitive.NewObjectID(), nil)
+				mockScheduleStore.EXPECT().AdvanceNextRun(gomock.Any(), dueSchedule.ID, dueSchedule.NextRunAt, wantNextRunAt).Return(false, assert.AnError)
+			},
+		},
+		{
+			name:             "should return error when due schedules cannot be fetched",
+			wantMaterialized: 0,
+			wantErr:          true,
+			beforeSuite: func() {
+				mockScheduleStore.EXPECT().FetchDueSchedules(gomock.Any(), now, config.BatchSize).Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			scheduler := NewRecurringScheduler(mockScheduleStore, mockMessageStore, config, zap.NewNop())
+			got, err := scheduler.MaterializeDue(context.Background(), now)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantMaterialized, got)
+		})
+	}
+}
+
+func TestRecurringScheduler_StartWithZeroInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No store calls are expected: a zero interval must not start the loop.
+	scheduler := NewRecurringScheduler(NewMockRecurringScheduleStore(ctrl), NewMockRecurringMessageStore(ctrl), RecurringSchedulerConfig{}, zap.NewNop())
+
+	assert.NotPanics(t, scheduler.Start)
+	scheduler.Stop()
+}
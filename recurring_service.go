@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvalidCronExpression = errors.New("invalid cron expression")
+	ErrInvalidScheduleID     = errors.New("invalid schedule ID")
+)
+
+type RecurringScheduleRepository interface {
+	InsertSchedule(ctx context.Context, schedule *RecurringSchedule) (primitive.ObjectID, error)
+	RetrieveSchedules(ctx context.Context) ([]RecurringSchedule, error)
+	RetrieveSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error)
+	UpdateScheduleStatus(ctx context.Context, scheduleID primitive.ObjectID, status string, nextRunAt time.Time) error
+	DeleteSchedule(ctx context.Context, scheduleID primitive.ObjectID) error
+}
+
+type RecurringScheduleServiceImpl struct {
+	scheduleRepository RecurringScheduleRepository
+	config             Config
+	validate           *validator.Validate
+}
+
+func NewRecurringScheduleServiceImpl(rr RecurringScheduleRepository, cfg Config, validate *validator.Validate) *RecurringScheduleServiceImpl {
+	return &RecurringScheduleServiceImpl{
+		scheduleRepository: rr,
+		config:             cfg,
+		validate:           validate,
+	}
+}
+
+// NextOccurrence returns the first occurrence of a standard five-field cron
+// expression strictly after the given time, evaluated in the given timezone.
+func NextOccurrence(cronExpression, timezone string, after time.Time) (time.Time, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidCronExpression, err.Error())
+	}
+
+	return schedule.Next(after.In(location)).UTC(), nil
+}
+
+func (rs *RecurringScheduleServiceImpl) CreateSchedule(ctx context.Context, req CreateRecurringScheduleRequest) (primitive.ObjectID, error) {
+	now := time.Now()
+	schedule := &RecurringSchedule{
+		CronExpression:       req.CronExpression,
+		Timezone:             req.Timezone,
+		Content:              req.Content,
+		RecipientPhoneNumber: req.RecipientPhoneNumber,
+		Status:               RecurringStatusActive,
+		CreatedAt:            now,
+	}
+
+	if err := rs.validate.Struct(schedule); err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+	if err := normalizePhoneNumberField(&schedule.RecipientPhoneNumber, rs.config.Phone.DefaultRegion); err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	nextRunAt, err := NextOccurrence(schedule.CronExpression, schedule.Timezone, now)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+	schedule.NextRunAt = nextRunAt
+
+	id, err := rs.scheduleRepository.InsertSchedule(ctx, schedule)
+	if err != nil {
+		return primitive.NilObjectID, ErrInternalServerError
+	}
+
+	return id, nil
+}
+
+func (rs *RecurringScheduleServiceImpl) RetrieveSchedules(ctx context.Context) ([]RecurringSchedule, error) {
+	schedules, err := rs.scheduleRepository.RetrieveSchedules(ctx)
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if len(schedules) == 0 {
+		return nil, ErrDocumentNotFound
+	}
+
+	return schedules, nil
+}
+
+func (rs *RecurringScheduleServiceImpl) PauseSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error) {
+	schedule, err := rs.retrieveSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rs.scheduleRepository.UpdateScheduleStatus(ctx, scheduleID, RecurringStatusPaused, schedule.NextRunAt); err != nil {
+		return nil, rs.mapRepositoryError(err)
+	}
+
+	schedule.Status = RecurringStatusPaused
+	return schedule, nil
+}
+
+// ResumeSchedule reactivates a paused schedule starting from the next occurrence
+// after now, so occurrences missed while paused are not sent in a burst.
+func (rs *RecurringScheduleServiceImpl) ResumeSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error) {
+	schedule, err := rs.retrieveSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRunAt, err := NextOccurrence(schedule.CronExpression, schedule.Timezone, time.Now())
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if err := rs.scheduleRepository.UpdateScheduleStatus(ctx, scheduleID, RecurringStatusActive, nextRunAt); err != nil {
+		return nil, rs.mapRepositoryError(err)
+	}
+
+	schedule.Status = RecurringStatusActive
+	schedule.NextRunAt = nextRunAt
+	return schedule, nil
+}
+
+func (rs *RecurringScheduleServiceImpl) DeleteSchedule(ctx context.Context, scheduleID primitive.ObjectID) error {
+	if err := rs.scheduleRepository.DeleteSchedule(ctx, scheduleID); err != nil {
+		return rs.mapRepositoryError(err)
+	}
+
+	return nil
+}
+
+func (rs *RecurringScheduleServiceImpl) retrieveSchedule(ctx context.Context, scheduleID primitive.ObjectID) (*RecurringSchedule, error) {
+	schedule, err := rs.scheduleRepository.RetrieveSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, rs.mapRepositoryError(err)
+	}
+
+	return schedule, nil
+}
+
+func (rs *RecurringScheduleServiceImpl) mapRepositoryError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrDocumentNotFound
+	}
+	return ErrInternalServerError
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name           string
+		cronExpression string
+		timezone       string
+		after          time.Time
+		want           time.Time
+		wantErr        bool
+	}{
+		{
+			name:           "should return next daily occurrence in UTC",
+			cronExpression: "0 9 * * *",
+			timezone:       "UTC",
+			after:          time.Date(2025, 5, 10, 10, 0, 0, 0, time.UTC),
+			want:           time.Date(2025, 5, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "should evaluate expression in given timezone",
+			cronExpression: "0 9 * * *",
+			timezone:       "Europe/Istanbul",
+			after:          time.Date(2025, 5, 10, 5, 0, 0, 0, time.UTC),
+			want:           time.Date(2025, 5, 10, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "should return error for invalid cron expression",
+			cronExpression: "every day",
+			timezone:       "UTC",
+			after:          time.Date(2025, 5, 10, 10, 0, 0, 0, time.UTC),
+			wantErr:        true,
+		},
+		{
+			name:           "should return error for unknown timezone",
+			cronExpression: "0 9 * * *",
+			timezone:       "Mars/Olympus",
+			after:          time.Date(2025, 5, 10, 10, 0, 0, 0, time.UTC),
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextOccurrence(tt.cronExpression, tt.timezone, tt.after)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecurringService_CreateSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRecurringScheduleRepository(ctrl)
+	service := NewRecurringScheduleServiceImpl(mockRepo, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	createdID := primitive.NewObjectID()
+	validRequest := CreateRecurringScheduleRequest{
+		CronExpression:       "0 9 * * 1",
+		Timezone:             "Europe/Istanbul",
+		Content:              "Weekly reminder",
+		RecipientPhoneNumber: "+15553579024",
+	}
+
+	tests := []struct {
+		name        string
+		req         CreateRecurringScheduleRequest
+		wantID      primitive.ObjectID
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should create active schedule with next run time",
+			req:     validRequest,
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertSchedule(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, schedule *RecurringSchedule) (primitive.ObjectID, error) {
+					assert.Equal(t, RecurringStatusActive, schedule.Status)
+					assert.True(t, schedule.NextRunAt.After(time.Now()))
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name: "should store the normalized recipient",
+			req: CreateRecurringScheduleRequest{
+				CronExpression:       "0 9 * * 1",
+				Timezone:             "Europe/Istanbul",
+				Content:              "Weekly reminder",
+				RecipientPhoneNumber: "0555 111 22 33",
+			},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertSchedule(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, schedule *RecurringSchedule) (primitive.ObjectID, error) {
+					assert.Equal(t, "+905551112233", schedule.RecipientPhoneNumber)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name: "should return validation error for invalid cron expression",
+			req: CreateRecurringScheduleRequest{
+				CronExpression:       "every monday",
+				Timezone:             "UTC",
+				Content:              "Weekly reminder",
+				RecipientPhoneNumber: "+15553579024",
+			},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name: "should return validation error for invalid timezone",
+			req: CreateRecurringScheduleRequest{
+				CronExpression:       "0 9 * * 1",
+				Timezone:             "Mars/Olympus",
+				Content:              "Weekly reminder",
+				RecipientPhoneNumber: "+15553579024",
+			},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should return error when repository fails",
+			req:     validRequest,
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertSchedule(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.CreateSchedule(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantID, got)
+		})
+	}
+}
+
+func TestRecurringService_ResumeSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockRecurringScheduleRepository(ctrl)
+	service := NewRecurringScheduleServiceImpl(mockRepo, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	scheduleID := primitive.NewObjectID()
+	pausedSchedule := &RecurringSchedule{
+		ID:             scheduleID,
+		CronExpression: "*/5 * * * *",
+		Timezone:       "UTC",
+		Status:         RecurringStatusPaused,
+		NextRunAt:      time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name        string
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should resume schedule from next occurrence after now",
+			wantErr: nil,
+			beforeSuite: func() {
+				schedule := *pausedSchedule
+				mockRepo.EXPECT().RetrieveSchedule(gomock.Any(), scheduleID).Return(&schedule, nil)
+				mockRepo.EXPECT().UpdateScheduleStatus(gomock.Any(), scheduleID, RecurringStatusActive, gomock.Any()).DoAndReturn(func(_ context.Context, _ primitive.ObjectID, _ string, nextRunAt time.Time) error {
+					assert.True(t, nextRunAt.After(time.Now()))
+					return nil
+				})
+			},
+		},
+		{
+			name:    "should return not found when schedule does not exist",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveSchedule(gomock.Any(), scheduleID).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return internal error when update fails",
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				schedule := *pausedSchedule
+				mockRepo.EXPECT().RetrieveSchedule(gomock.Any(), scheduleID).Return(&schedule, nil)
+				mockRepo.EXPECT().UpdateScheduleStatus(gomock.Any(), scheduleID, RecurringStatusActive, gomock.Any()).Return(assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.ResumeSchedule(context.Background(), scheduleID)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, RecurringStatusActive, got.Status)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"math"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -21,11 +24,43 @@ const (
 	StatusProcessing     = "processing"
 	StatusFailed         = "failed"
 	StatusInvalidContent = "invalid_content"
+	StatusStuck          = "stuck"
+	StatusCancelled      = "cancelled"
+	StatusExpired        = "expired"
+	StatusSuppressed     = "suppressed"
+	StatusDuplicate      = "duplicate"
 )
 
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// priorityRanks orders priorities for FetchAndMarkProcessing, lowest rank first.
+var priorityRanks = map[string]int{
+	PriorityHigh:   1,
+	PriorityNormal: 2,
+	PriorityLow:    3,
+}
+
+func priorityRank(priority string) int {
+	if rank, ok := priorityRanks[priority]; ok {
+		return rank
+	}
+
+	return priorityRanks[PriorityNormal]
+}
+
 var (
 	ErrDocumentDecodingFailed = errors.New("document decoding failed")
 	ErrInvalidMessageID       = errors.New("invalid message ID")
+	ErrInvalidPageToken       = errors.New("invalid page token")
+	ErrMessageNotEditable     = errors.New("message is no longer unsent")
+	ErrMessageHeld            = errors.New("message belongs to a paused or cancelled campaign")
+
+	ErrScheduledAfterExpiry       = errors.New("scheduled_at must be before expires_at")
+	ErrTemplateContentNotEditable = errors.New("content of a template message cannot be edited")
 )
 
 type MessageRepositoryImpl struct {
@@ -38,19 +73,119 @@ func NewMessageRepositoryImpl(collection *mongo.Collection) *MessageRepositoryIm
 	}
 }
 
-func (mr *MessageRepositoryImpl) FetchAndMarkProcessing(ctx context.Context) (*Message, error) {
+// EnsureIndexes creates the indexes the worker fetch query and the API rely on.
+// Messages stored before priorities existed are backfilled with the rank of
+// their priority, normal when they have none, so the claim order treats them
+// like any other message. The unique index on recurring_schedule_id and
+// scheduled_at keeps a recurring occurrence from being enqueued twice.
+func (mr *MessageRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	backfill := bson.A{
+		bson.M{"$set": bson.M{
+			"priority": bson.M{"$ifNull": bson.A{"$priority", PriorityNormal}},
+			"priority_rank": bson.M{"$switch": bson.M{
+				"branches": bson.A{
+					bson.M{"case": bson.M{"$eq": bson.A{"$priority", PriorityHigh}}, "then": priorityRank(PriorityHigh)},
+					bson.M{"case": bson.M{"$eq": bson.A{"$priority", PriorityLow}}, "then": priorityRank(PriorityLow)},
+				},
+				"default": priorityRank(PriorityNormal),
+			}},
+		}},
+	}
+	if _, err := mr.messageCollection.UpdateMany(ctx, bson.M{"priority_rank": bson.M{"$exists": false}}, backfill); err != nil {
+		return err
+	}
+
+	_, err := mr.messageCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "priority_rank", Value: 1}, {Key: "scheduled_at", Value: 1}, {Key: "created_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "processing_started_at", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "failed_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "sent_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "recipient_phone_number", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "campaign_id", Value: 1}, {Key: "status", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "external_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"external_id": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"tags": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"expires_at": bson.M{"$exists": true}}),
+		},
+		{
+			Keys: bson.D{{Key: "recurring_schedule_id", Value: 1}, {Key: "scheduled_at", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"recurring_schedule_id": bson.M{"$exists": true}}),
+		},
+	})
+
+	return err
+}
+
+// FetchAndMarkProcessing claims the next due message for the given worker,
+// highest priority first and oldest first within a priority. A non-empty
+// priority only claims messages of that priority. The claim is a lease:
+// processing_started_at and worker_id let the reaper find messages whose
+// worker died before finishing them.
+func (mr *MessageRepositoryImpl) FetchAndMarkProcessing(ctx context.Context, workerID string, priority string) (*Message, error) {
+	now := time.Now()
+
 	filter := bson.M{
 		"status": StatusUnsent,
+		"held":   bson.M{"$ne": true}, // messages of a paused or cancelled campaign
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"scheduled_at": bson.M{"$lte": now}},
+				bson.M{"scheduled_at": bson.M{"$exists": false}}, // documents created before scheduling existed
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"next_attempt_at": bson.M{"$lte": now}},
+				bson.M{"next_attempt_at": bson.M{"$exists": false}},
+			}},
+		},
+	}
+
+	if priority != "" {
+		filter["priority_rank"] = priorityRank(priority)
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"status": StatusProcessing,
+			"status":                StatusProcessing,
+			"worker_id":             workerID,
+			"processing_started_at": now,
+		},
+		"$unset": bson.M{
+			"dispatch_started_at": "",
+		},
+		"$inc": bson.M{
+			"attempts": 1,
 		},
 	}
 
 	opts := options.FindOneAndUpdate().
-		SetSort(bson.M{"created_at": 1}).
+		SetSort(bson.D{{Key: "priority_rank", Value: 1}, {Key: "scheduled_at", Value: 1}, {Key: "created_at", Value: 1}}).
 		SetReturnDocument(options.After)
 
 	var message Message
@@ -66,6 +201,301 @@ func (mr *MessageRepositoryImpl) FetchAndMarkProcessing(ctx context.Context) (*M
 	return &message, nil
 }
 
+// leaseFilter matches a claimed message only while it is still processing
+// under the worker that claimed it. Every worker-side transition uses it, so a
+// worker whose lease was reaped, or whose message another worker claimed again,
+// gets mongo.ErrNoDocuments instead of overwriting the current holder's state.
+func leaseFilter(message *Message) bson.M {
+	return bson.M{
+		"_id":       message.ID,
+		"status":    StatusProcessing,
+		"worker_id": message.WorkerID,
+	}
+}
+
+// MarkAsDispatching records that the webhook call for a claimed message is about
+// to be made. Once set, an expired lease can no longer be retried blindly. For a
+// template message it also stores the content the template was rendered to and
+// its encoding and segments, so the message shows what was actually sent.
+// The update only matches while the worker that claimed the message still holds
+// its lease; once the reaper released it or another worker claimed it again,
+// mongo.ErrNoDocuments is returned and the webhook must not be called.
+func (mr *MessageRepositoryImpl) MarkAsDispatching(ctx context.Context, message *Message) error {
+	filter := leaseFilter(message)
+
+	set := bson.M{
+		"dispatch_started_at": time.Now(),
+	}
+	if message.TemplateID != nil {
+		set["content"] = message.Content
+		set["encoding"] = message.Encoding
+		set["segments"] = message.Segments
+	}
+
+	update := bson.M{
+		"$set": set,
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// ReleaseExpiredLeases returns messages that stayed in processing longer than
+// the lease to unsent, as long as their webhook call never started. The claim
+// is not counted as a delivery attempt. Messages of a cancelled campaign are
+// cancelled instead. Messages that may already have reached the webhook are
+// left to the caller, see RetrieveExpiredDispatches.
+func (mr *MessageRepositoryImpl) ReleaseExpiredLeases(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	filter := bson.M{
+		"status":                StatusProcessing,
+		"processing_started_at": bson.M{"$lt": expiredBefore},
+		"dispatch_started_at":   bson.M{"$exists": false},
+	}
+
+	cancelled, err := mr.messageCollection.UpdateMany(ctx,
+		bson.M{"$and": bson.A{filter, bson.M{"campaign_cancelled": true}}},
+		cancelClaimedUpdate(),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := mr.messageCollection.UpdateMany(ctx,
+		bson.M{"$and": bson.A{filter, bson.M{"campaign_cancelled": bson.M{"$ne": true}}}},
+		bson.M{
+			"$set": bson.M{
+				"status": StatusUnsent,
+			},
+			"$unset": bson.M{
+				"worker_id":             "",
+				"processing_started_at": "",
+			},
+			"$inc": bson.M{
+				"attempts": -1,
+			},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return cancelled.ModifiedCount + result.ModifiedCount, nil
+}
+
+// RetrieveExpiredDispatches returns the messages whose lease expired after their
+// webhook call started. Whether such a message was delivered is only known from
+// the worker's sent record, so they are not resent.
+func (mr *MessageRepositoryImpl) RetrieveExpiredDispatches(ctx context.Context, expiredBefore time.Time) ([]Message, error) {
+	filter := bson.M{
+		"status":                StatusProcessing,
+		"processing_started_at": bson.M{"$lt": expiredBefore},
+		"dispatch_started_at":   bson.M{"$exists": true},
+	}
+
+	cursor, err := mr.messageCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var messages []Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, ErrDocumentDecodingFailed
+	}
+
+	return messages, nil
+}
+
+// DeferMessage returns a claimed message to the queue until its delivery window
+// opens. The claim is not counted as a delivery attempt. A message of a
+// cancelled campaign is cancelled instead, see cancelClaimedMessage.
+func (mr *MessageRepositoryImpl) DeferMessage(ctx context.Context, message *Message, until time.Time) error {
+	filter := leaseFilter(message)
+	filter["campaign_cancelled"] = bson.M{"$ne": true}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":          StatusUnsent,
+			"next_attempt_at": until,
+			"deferred_until":  until,
+		},
+		"$unset": bson.M{
+			"worker_id":             "",
+			"processing_started_at": "",
+		},
+		"$inc": bson.M{
+			"attempts":  -1,
+			"deferrals": 1,
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mr.cancelClaimedMessage(ctx, message)
+	}
+
+	return nil
+}
+
+// MarkAsSuppressed ends delivery of a message whose recipient is on the
+// suppression list.
+func (mr *MessageRepositoryImpl) MarkAsSuppressed(ctx context.Context, message *Message, reason string) error {
+	filter := leaseFilter(message)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status": StatusSuppressed,
+			"err":    reason,
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// MarkAsDuplicate ends delivery of a message that repeats the content of an
+// earlier message to the same recipient, linking it to that message.
+func (mr *MessageRepositoryImpl) MarkAsDuplicate(ctx context.Context, message *Message, originalID primitive.ObjectID) error {
+	filter := leaseFilter(message)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":       StatusDuplicate,
+			"duplicate_of": originalID,
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// MarkAsExpired ends delivery of a claimed message whose expires_at has passed.
+func (mr *MessageRepositoryImpl) MarkAsExpired(ctx context.Context, message *Message) error {
+	filter := leaseFilter(message)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     StatusExpired,
+			"expired_at": time.Now(),
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// ExpireMessages moves every unsent message whose expires_at is not after now
+// to expired, so stale messages do not wait in the queue for a worker.
+func (mr *MessageRepositoryImpl) ExpireMessages(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{
+		"status":     StatusUnsent,
+		"expires_at": bson.M{"$lte": now},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     StatusExpired,
+			"expired_at": now,
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// MarkAsInvalidContent ends delivery of a message whose content can never be
+// sent as is, e.g. a template rendering that failed.
+func (mr *MessageRepositoryImpl) MarkAsInvalidContent(ctx context.Context, message *Message, reason string) error {
+	filter := leaseFilter(message)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":        StatusInvalidContent,
+			"err":           reason,
+			"failure_class": FailureClassInvalid,
+			"failed_at":     time.Now(),
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// MarkAsStuck parks a claimed message that may already have been delivered, so
+// it is not sent again without an operator looking at it. Like MarkAsDispatching
+// it only matches while the message is still processing under the worker that
+// claimed it, so a message that was meanwhile marked as sent stays sent.
+func (mr *MessageRepositoryImpl) MarkAsStuck(ctx context.Context, message *Message, reason string) error {
+	filter := leaseFilter(message)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status": StatusStuck,
+			"err":    reason,
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
 func (mr *MessageRepositoryImpl) MarkAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error {
 	now := time.Now()
 
@@ -96,49 +526,703 @@ func (mr *MessageRepositoryImpl) MarkAsSent(ctx context.Context, messageID primi
 	return nil
 }
 
-func (mr *MessageRepositoryImpl) MarkAsFailed(ctx context.Context, messageID primitive.ObjectID, errmsg string) error {
+// ScheduleRetry returns a message whose delivery attempt failed to unsent so it
+// is claimed again once nextAttemptAt has passed. A message of a cancelled
+// campaign is cancelled instead, see cancelClaimedMessage.
+func (mr *MessageRepositoryImpl) ScheduleRetry(ctx context.Context, message *Message, nextAttemptAt time.Time, failure DeliveryFailure) error {
+	filter := leaseFilter(message)
+	filter["campaign_cancelled"] = bson.M{"$ne": true}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":              StatusUnsent,
+			"next_attempt_at":     nextAttemptAt,
+			"err":                 failure.Reason,
+			"failure_class":       failure.Class,
+			"failure_status_code": failure.StatusCode,
+		},
+		"$unset": bson.M{
+			"worker_id":             "",
+			"processing_started_at": "",
+			"dispatch_started_at":   "",
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mr.cancelClaimedMessage(ctx, message)
+	}
+
+	return nil
+}
+
+// cancelClaimedMessage cancels a claimed message of a cancelled campaign that
+// would otherwise go back to the queue, where it would stay held forever. The
+// campaign is checked after the regular update missed, so a cancel that lands
+// between the two is still seen. mongo.ErrNoDocuments is returned when the
+// worker lost its lease.
+func (mr *MessageRepositoryImpl) cancelClaimedMessage(ctx context.Context, message *Message) error {
+	filter := leaseFilter(message)
+	filter["campaign_cancelled"] = true
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, cancelClaimedUpdate())
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func cancelClaimedUpdate() bson.M {
+	return bson.M{
+		"$set": bson.M{
+			"status":       StatusCancelled,
+			"cancelled_at": time.Now(),
+		},
+		"$unset": bson.M{
+			"worker_id":             "",
+			"processing_started_at": "",
+			"dispatch_started_at":   "",
+		},
+	}
+}
+
+func (mr *MessageRepositoryImpl) MarkAsFailed(ctx context.Context, message *Message, failure DeliveryFailure) error {
+	filter := leaseFilter(message)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":              StatusFailed,
+			"failed_at":           time.Now(),
+			"err":                 failure.Reason,
+			"failure_class":       failure.Class,
+			"failure_status_code": failure.StatusCode,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After)
+	var failed Message
+	err := mr.messageCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&failed)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return mongo.ErrNoDocuments
+		}
+		return err
+	}
+
+	return nil
+}
+
+// RecordAttempt appends a webhook call to the message's delivery history.
+func (mr *MessageRepositoryImpl) RecordAttempt(ctx context.Context, messageID primitive.ObjectID, attempt DeliveryAttempt) error {
+	result, err := mr.messageCollection.UpdateOne(ctx,
+		bson.M{"_id": messageID},
+		bson.M{"$push": bson.M{"history": attempt}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (mr *MessageRepositoryImpl) RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error) {
+	var message Message
+	err := mr.messageCollection.FindOne(ctx, bson.M{"_id": messageID}).Decode(&message)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// CancelMessage moves an unsent message to cancelled. The status condition makes
+// the update atomic with FetchAndMarkProcessing: whichever runs first wins.
+func (mr *MessageRepositoryImpl) CancelMessage(ctx context.Context, messageID primitive.ObjectID) error {
 	filter := bson.M{
-		"_id": messageID,
+		"_id":    messageID,
+		"status": StatusUnsent,
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"status": StatusFailed,
-			"err":    errmsg,
+			"status":       StatusCancelled,
+			"cancelled_at": time.Now(),
 		},
 	}
 
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mr.notEditableError(ctx, messageID)
+	}
+
+	return nil
+}
+
+// UpdateMessage applies the non-nil fields of the request to an unsent message
+// and returns the updated message. The content of a template message is only
+// known once it is rendered, so it cannot be edited. A new scheduled_at must be
+// before the message's expires_at, and it replaces any retry backoff or
+// delivery window deferral, which were computed for the old schedule.
+func (mr *MessageRepositoryImpl) UpdateMessage(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) (*Message, error) {
+	filter := bson.M{
+		"_id":    messageID,
+		"status": StatusUnsent,
+	}
+
+	fields := bson.M{}
+	update := bson.M{"$set": fields}
+	if req.Content != nil {
+		filter["template_id"] = nil
+		fields["content"] = *req.Content
+		fields["encoding"], fields["segments"] = MeasureSegments(*req.Content)
+	}
+	if req.RecipientPhoneNumber != nil {
+		fields["recipient_phone_number"] = *req.RecipientPhoneNumber
+	}
+	if req.ScheduledAt != nil {
+		filter["expires_at"] = bson.M{"$not": bson.M{"$lte": *req.ScheduledAt}}
+		fields["scheduled_at"] = *req.ScheduledAt
+		update["$unset"] = bson.M{"next_attempt_at": "", "deferred_until": ""}
+	}
+
 	opts := options.FindOneAndUpdate().
 		SetReturnDocument(options.After)
 	var message Message
 	err := mr.messageCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return mongo.ErrNoDocuments
+			return nil, mr.updateRejectedError(ctx, messageID, req)
 		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// updateRejectedError tells why a conditional UpdateMessage matched nothing.
+func (mr *MessageRepositoryImpl) updateRejectedError(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) error {
+	opts := options.FindOne().
+		SetProjection(bson.M{"status": 1, "expires_at": 1, "template_id": 1})
+
+	var message Message
+	if err := mr.messageCollection.FindOne(ctx, bson.M{"_id": messageID}, opts).Decode(&message); err != nil {
 		return err
 	}
 
-	return nil
+	switch {
+	case message.Status != StatusUnsent:
+		return ErrMessageNotEditable
+	case req.Content != nil && message.TemplateID != nil:
+		return ErrTemplateContentNotEditable
+	case req.ScheduledAt != nil && message.ExpiresAt != nil && !req.ScheduledAt.Before(*message.ExpiresAt):
+		return ErrScheduledAfterExpiry
+	default:
+		// The message changed between the update and this read.
+		return ErrMessageNotEditable
+	}
 }
 
-func (mr *MessageRepositoryImpl) RetrieveSentMessages() ([]Message, error) {
-	ctx := context.Background()
+// notEditableError tells apart a missing message from one that has already
+// left the unsent status after a conditional update matched nothing.
+func (mr *MessageRepositoryImpl) notEditableError(ctx context.Context, messageID primitive.ObjectID) error {
+	count, err := mr.messageCollection.CountDocuments(ctx, bson.M{"_id": messageID})
+	if err != nil {
+		return err
+	}
 
-	filter := bson.M{"status": StatusSent} // not actually required, but for clarity
-	sort := bson.M{"sent_at": -1}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
 
-	cursor, err := mr.messageCollection.Find(ctx, filter, options.Find().SetSort(sort))
+	return ErrMessageNotEditable
+}
+
+func (mr *MessageRepositoryImpl) InsertMessage(ctx context.Context, message *Message) (primitive.ObjectID, error) {
+	result, err := mr.messageCollection.InsertOne(ctx, message)
 	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, ErrInvalidMessageID
+	}
+
+	return id, nil
+}
+
+// InsertMessages inserts a batch without stopping at the first failing document.
+// It returns the reason for every document that was not inserted, keyed by its
+// index in the batch; an error means the batch as a whole failed.
+func (mr *MessageRepositoryImpl) InsertMessages(ctx context.Context, messages []Message) (map[int]string, error) {
+	documents := make([]interface{}, len(messages))
+	for i := range messages {
+		documents[i] = messages[i]
+	}
+
+	_, err := mr.messageCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return nil, nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
 		return nil, err
 	}
 
+	failed := make(map[int]string, len(bulkErr.WriteErrors))
+	for _, writeErr := range bulkErr.WriteErrors {
+		failed[writeErr.Index] = writeErr.Message
+	}
+
+	return failed, nil
+}
+
+// SetCampaignHold holds or releases every message of a campaign. Held messages
+// are skipped by FetchAndMarkProcessing. The flag is set regardless of status,
+// so a message that is in flight while the campaign is paused stays held if it
+// is scheduled for a retry.
+func (mr *MessageRepositoryImpl) SetCampaignHold(ctx context.Context, campaignID primitive.ObjectID, held bool) (int64, error) {
+	update := bson.M{"$unset": bson.M{"held": ""}}
+	if held {
+		update = bson.M{"$set": bson.M{"held": true}}
+	}
+
+	result, err := mr.messageCollection.UpdateMany(ctx, bson.M{"campaign_id": campaignID}, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// CancelCampaignMessages cancels every message of a campaign that is still
+// unsent. Messages already claimed by a worker finish their current attempt;
+// they are flagged first, so one that is scheduled for a retry or deferred
+// afterwards is cancelled rather than returned to the queue.
+func (mr *MessageRepositoryImpl) CancelCampaignMessages(ctx context.Context, campaignID primitive.ObjectID) (int64, error) {
+	if _, err := mr.messageCollection.UpdateMany(ctx,
+		bson.M{"campaign_id": campaignID},
+		bson.M{"$set": bson.M{"campaign_cancelled": true}},
+	); err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{
+		"campaign_id": campaignID,
+		"status":      StatusUnsent,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":       StatusCancelled,
+			"cancelled_at": time.Now(),
+		},
+	}
+
+	result, err := mr.messageCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// CountCampaignMessages returns the number of messages of a campaign per status.
+func (mr *MessageRepositoryImpl) CountCampaignMessages(ctx context.Context, campaignID primitive.ObjectID) (map[string]int64, error) {
+	return mr.countByStatus(ctx, bson.M{"campaign_id": campaignID})
+}
+
+// CountMessagesByStatus returns the number of messages per status.
+func (mr *MessageRepositoryImpl) CountMessagesByStatus(ctx context.Context) (map[string]int64, error) {
+	return mr.countByStatus(ctx, bson.M{})
+}
+
+// RetrieveOldestDueTime returns since when the unsent message that has been
+// due the longest is due. A message is due under the same conditions
+// FetchAndMarkProcessing claims it on, from the later of its scheduled_at and
+// next_attempt_at, so messages deferred to a delivery window or waiting out a
+// retry backoff are not counted as overdue. Messages of paused campaigns are
+// not waiting on the workers and are left out. It returns
+// mongo.ErrNoDocuments when no message is due.
+func (mr *MessageRepositoryImpl) RetrieveOldestDueTime(ctx context.Context, now time.Time) (time.Time, error) {
+	// scheduled_at is missing on documents created before scheduling existed,
+	// and $toDate also reads the string timestamps of raw seed documents.
+	scheduledAt := bson.M{"$ifNull": bson.A{bson.M{"$toDate": "$scheduled_at"}, bson.M{"$toDate": "$created_at"}}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"status": StatusUnsent,
+			"held":   bson.M{"$ne": true},
+			"$and": bson.A{
+				bson.M{"$or": bson.A{
+					bson.M{"scheduled_at": bson.M{"$lte": now}},
+					bson.M{"scheduled_at": bson.M{"$exists": false}},
+				}},
+				bson.M{"$or": bson.A{
+					bson.M{"next_attempt_at": bson.M{"$lte": now}},
+					bson.M{"next_attempt_at": bson.M{"$exists": false}},
+				}},
+			},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"due_at": bson.M{"$max": bson.A{scheduledAt, bson.M{"$ifNull": bson.A{"$next_attempt_at", scheduledAt}}}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"due_at": 1}}},
+		{{Key: "$limit", Value: 1}},
+	}
+
+	cursor, err := mr.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var due []struct {
+		DueAt time.Time `bson:"due_at"`
+	}
+	if err := cursor.All(ctx, &due); err != nil {
+		return time.Time{}, ErrDocumentDecodingFailed
+	}
+
+	if len(due) == 0 {
+		return time.Time{}, mongo.ErrNoDocuments
+	}
+
+	return due[0].DueAt, nil
+}
+
+// SentMessageStats returns how many messages were sent since the given time
+// and the approximate p50, p95 and p99 of their time from created_at to
+// sent_at.
+func (mr *MessageRepositoryImpl) SentMessageStats(ctx context.Context, since time.Time) (int64, LatencyPercentiles, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": StatusSent, "sent_at": bson.M{"$gte": since}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"count": bson.M{"$sum": 1},
+			"latency": bson.M{"$percentile": bson.M{
+				"input":  bson.M{"$subtract": bson.A{"$sent_at", bson.M{"$toDate": "$created_at"}}}, // milliseconds
+				"p":      bson.A{0.5, 0.95, 0.99},
+				"method": "approximate",
+			}},
+		}}},
+	}
+
+	cursor, err := mr.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, LatencyPercentiles{}, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Count   int64     `bson:"count"`
+		Latency []float64 `bson:"latency"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return 0, LatencyPercentiles{}, ErrDocumentDecodingFailed
+	}
+
+	if len(groups) == 0 || len(groups[0].Latency) != 3 {
+		return 0, LatencyPercentiles{}, nil
+	}
+
+	latency := groups[0].Latency
+	return groups[0].Count, LatencyPercentiles{
+		P50: int64(math.Round(latency[0])),
+		P95: int64(math.Round(latency[1])),
+		P99: int64(math.Round(latency[2])),
+	}, nil
+}
+
+func (mr *MessageRepositoryImpl) countByStatus(ctx context.Context, match bson.M) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := mr.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, ErrDocumentDecodingFailed
+	}
+
+	counts := make(map[string]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Status] = group.Count
+	}
+
+	return counts, nil
+}
+
+// pageCursor is the position of the last message of a page. It is encoded into
+// an opaque page token so clients cannot depend on its shape.
+type pageCursor struct {
+	SortValue time.Time          `json:"v"`
+	ID        primitive.ObjectID `json:"id"`
+}
+
+func encodePageToken(cursor pageCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageToken(token string) (*pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	return &cursor, nil
+}
+
+func messageSortValue(message Message, sortField string) time.Time {
+	if sortField == "sent_at" {
+		return message.SentAt
+	}
+	return message.CreatedAt
+}
+
+// ListMessages returns one page of messages matching the filter, newest first by
+// the filter's sort field, plus the token of the next page ("" on the last page).
+func (mr *MessageRepositoryImpl) ListMessages(ctx context.Context, messageFilter MessageFilter) ([]Message, string, error) {
+	sortField := "created_at"
+	if messageFilter.SortBy == "sent_at" {
+		sortField = "sent_at"
+	}
+
+	conditions := bson.A{}
+
+	if messageFilter.Status != "" {
+		conditions = append(conditions, bson.M{"status": messageFilter.Status})
+	}
+
+	if messageFilter.RecipientPhoneNumber != "" {
+		conditions = append(conditions, bson.M{"recipient_phone_number": messageFilter.RecipientPhoneNumber})
+	}
+
+	if messageFilter.ExternalID != "" {
+		conditions = append(conditions, bson.M{"external_id": messageFilter.ExternalID})
+	}
+
+	if messageFilter.Tag != "" {
+		conditions = append(conditions, bson.M{"tags": messageFilter.Tag})
+	}
+
+	if dateRange := timeRange(messageFilter.CreatedAfter, messageFilter.CreatedBefore); dateRange != nil {
+		conditions = append(conditions, bson.M{"created_at": dateRange})
+	}
+
+	if dateRange := timeRange(messageFilter.SentAfter, messageFilter.SentBefore); dateRange != nil {
+		conditions = append(conditions, bson.M{"sent_at": dateRange})
+	}
+
+	if messageFilter.PageToken != "" {
+		cursor, err := decodePageToken(messageFilter.PageToken)
+		if err != nil {
+			return nil, "", err
+		}
+
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{sortField: bson.M{"$lt": cursor.SortValue}},
+			bson.M{sortField: cursor.SortValue, "_id": bson.M{"$lt": cursor.ID}},
+		}})
+	}
+
+	filter := bson.M{}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(messageFilter.Limit + 1)). // one extra document tells whether another page exists
+		SetProjection(bson.M{"history": 0})       // history is only returned by the detail endpoint
+
+	cursor, err := mr.messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
 	defer cursor.Close(ctx)
 
 	var messages []Message
 	if err := cursor.All(ctx, &messages); err != nil {
-		return nil, ErrDocumentDecodingFailed // breaks the worker I know
+		return nil, "", ErrDocumentDecodingFailed
 	}
 
-	return messages, nil
+	if len(messages) <= messageFilter.Limit {
+		return messages, "", nil
+	}
+
+	messages = messages[:messageFilter.Limit]
+	last := messages[len(messages)-1]
+
+	return messages, encodePageToken(pageCursor{SortValue: messageSortValue(last, sortField), ID: last.ID}), nil
+}
+
+func timeRange(after, before *time.Time) bson.M {
+	if after == nil && before == nil {
+		return nil
+	}
+
+	dateRange := bson.M{}
+	if after != nil {
+		dateRange["$gte"] = *after
+	}
+	if before != nil {
+		dateRange["$lte"] = *before
+	}
+
+	return dateRange
+}
+
+func (mr *MessageRepositoryImpl) RetrieveFailedMessages(ctx context.Context, page, limit int) ([]Message, int64, error) {
+	filter := bson.M{"status": StatusFailed}
+
+	total, err := mr.messageCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "failed_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := mr.messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var messages []Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, 0, ErrDocumentDecodingFailed
+	}
+
+	return messages, total, nil
+}
+
+// requeueUpdate moves failed or stuck messages back to unsent with a fresh
+// attempt budget. Held messages of a paused or cancelled campaign are never
+// requeued, as the claim query would skip them and they would not be sent.
+var requeueUpdate = bson.M{
+	"$set": bson.M{
+		"status":   StatusUnsent,
+		"attempts": 0,
+	},
+	"$unset": bson.M{
+		"next_attempt_at":       "",
+		"failed_at":             "",
+		"err":                   "",
+		"failure_class":         "",
+		"failure_status_code":   "",
+		"worker_id":             "",
+		"processing_started_at": "",
+		"dispatch_started_at":   "",
+	},
+}
+
+// RequeueMessage moves a failed message back to unsent. ErrMessageHeld is
+// returned for a failed message of a paused or cancelled campaign.
+func (mr *MessageRepositoryImpl) RequeueMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	return mr.requeueMessage(ctx, messageID, StatusFailed)
+}
+
+// RequeueStuckMessage moves a stuck message back to unsent with a fresh attempt
+// budget. ErrMessageHeld is returned for a stuck message of a paused or
+// cancelled campaign.
+func (mr *MessageRepositoryImpl) RequeueStuckMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	return mr.requeueMessage(ctx, messageID, StatusStuck)
+}
+
+func (mr *MessageRepositoryImpl) requeueMessage(ctx context.Context, messageID primitive.ObjectID, status string) error {
+	filter := bson.M{
+		"_id":    messageID,
+		"status": status,
+		"held":   bson.M{"$ne": true},
+	}
+
+	result, err := mr.messageCollection.UpdateOne(ctx, filter, requeueUpdate)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		held, err := mr.messageCollection.CountDocuments(ctx, bson.M{"_id": messageID, "status": status, "held": true})
+		if err != nil {
+			return err
+		}
+		if held > 0 {
+			return ErrMessageHeld
+		}
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (mr *MessageRepositoryImpl) RequeueFailedMessages(ctx context.Context, requeueFilter RequeueFailedMessagesRequest) (int64, error) {
+	filter := bson.M{
+		"status": StatusFailed,
+		"held":   bson.M{"$ne": true},
+	}
+
+	if requeueFilter.FailureClass != "" {
+		filter["failure_class"] = requeueFilter.FailureClass
+	}
+
+	if requeueFilter.RecipientPhoneNumber != "" {
+		filter["recipient_phone_number"] = requeueFilter.RecipientPhoneNumber
+	}
+
+	if failedAt := timeRange(requeueFilter.FailedAfter, requeueFilter.FailedBefore); failedAt != nil {
+		filter["failed_at"] = failedAt
+	}
+
+	result, err := mr.messageCollection.UpdateMany(ctx, filter, requeueUpdate)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
 }
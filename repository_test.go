@@ -57,7 +57,7 @@ func prepareTestMongoStore() (*mongo.Client, func(), error) {
 	return client, cleanFunc, nil
 }
 
-func TestRepository_RetrieveSendMessage(t *testing.T) {
+func TestRepository_ListMessages(t *testing.T) {
 	sampleMixedMessagesFilePath := "sample/mixed_status_messages.json"
 	sampleMixedMessageContentRawByte, err := os.ReadFile(sampleMixedMessagesFilePath)
 	if err != nil {
@@ -71,14 +71,19 @@ func TestRepository_RetrieveSendMessage(t *testing.T) {
 		return
 	}
 
+	sentFilter := MessageFilter{Status: StatusSent, SortBy: "sent_at", Limit: 20}
+
 	tests := []struct {
-		name        string
-		wantData    []Message
-		wantErr     error
-		beforeSuite func() (*mongo.Client, func())
+		name          string
+		filter        MessageFilter
+		wantData      []Message
+		wantNextToken bool
+		wantErr       error
+		beforeSuite   func() (*mongo.Client, func())
 	}{
 		{
 			name:     "should return retrieved sent messages",
+			filter:   sentFilter,
 			wantData: []Message{sampleMixedMessages[4], sampleMixedMessages[0]}, // from newest to oldest sent_at
 			wantErr:  nil,
 			beforeSuite: func() (*mongo.Client, func()) {
@@ -97,8 +102,69 @@ func TestRepository_RetrieveSendMessage(t *testing.T) {
 				return client, cleanFunc
 			},
 		},
+		{
+			name:          "should return first page and next page token",
+			filter:        MessageFilter{Status: StatusSent, SortBy: "sent_at", Limit: 1},
+			wantData:      []Message{sampleMixedMessages[4]},
+			wantNextToken: true,
+			wantErr:       nil,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				var bsonMessages []interface{}
+				for _, message := range sampleMixedMessages {
+					bsonMessages = append(bsonMessages, message)
+				}
+
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertMany(context.Background(), bsonMessages)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name: "should return second page when page token is given",
+			filter: MessageFilter{
+				Status:    StatusSent,
+				SortBy:    "sent_at",
+				Limit:     1,
+				PageToken: encodePageToken(pageCursor{SortValue: sampleMixedMessages[4].SentAt, ID: sampleMixedMessages[4].ID}),
+			},
+			wantData: []Message{sampleMixedMessages[0]},
+			wantErr:  nil,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				var bsonMessages []interface{}
+				for _, message := range sampleMixedMessages {
+					bsonMessages = append(bsonMessages, message)
+				}
+
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertMany(context.Background(), bsonMessages)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:     "should return error when page token is invalid",
+			filter:   MessageFilter{Limit: 1, PageToken: "%%%"},
+			wantData: nil,
+			wantErr:  ErrInvalidPageToken,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
 		{
 			name:     "should return error when messages are not found",
+			filter:   sentFilter,
 			wantData: nil,
 			wantErr:  nil,
 			beforeSuite: func() (*mongo.Client, func()) {
@@ -110,6 +176,7 @@ func TestRepository_RetrieveSendMessage(t *testing.T) {
 		},
 		{
 			name:     "should return error when decoding fails",
+			filter:   sentFilter,
 			wantData: nil,
 			wantErr:  ErrDocumentDecodingFailed,
 			beforeSuite: func() (*mongo.Client, func()) {
@@ -153,9 +220,10 @@ func TestRepository_RetrieveSendMessage(t *testing.T) {
 			defer cleanFunc()
 
 			messageRepository := NewMessageRepositoryImpl(client.Database(testDB).Collection(testCollection))
-			gotData, err := messageRepository.RetrieveSentMessages()
+			gotData, nextToken, err := messageRepository.ListMessages(context.Background(), tt.filter)
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantData, gotData)
+			assert.Equal(t, tt.wantNextToken, nextToken != "")
 		})
 	}
 }
@@ -174,8 +242,12 @@ func TestRepository_FetchAndMarkProcessing(t *testing.T) {
 		return
 	}
 
+	highPriorityID := primitive.NewObjectID()
+	lowPriorityID := primitive.NewObjectID()
+
 	tests := []struct {
 		name          string
+		priority      string
 		wantMessageID primitive.ObjectID
 		wantErr       bool
 		wantStatus    string
@@ -214,6 +286,128 @@ func TestRepository_FetchAndMarkProcessing(t *testing.T) {
 				return client, cleanFunc
 			},
 		},
+		{
+			name:          "should not return message waiting for retry backoff",
+			wantMessageID: primitive.NilObjectID,
+			wantErr:       true,
+			wantStatus:    "",
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				backoffMessage := Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Retry me later",
+					RecipientPhoneNumber: "+15553579024",
+					Status:               StatusUnsent,
+					Attempts:             1,
+					CreatedAt:            time.Now(),
+					ScheduledAt:          time.Now(),
+					NextAttemptAt:        time.Now().Add(time.Hour),
+				}
+
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertOne(context.Background(), backoffMessage)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:          "should not return message scheduled in the future",
+			wantMessageID: primitive.NilObjectID,
+			wantErr:       true,
+			wantStatus:    "",
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				futureMessage := Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Reminder: your appointment is tomorrow.",
+					RecipientPhoneNumber: "+15553579024",
+					Status:               StatusUnsent,
+					CreatedAt:            time.Now(),
+					ScheduledAt:          time.Now().Add(24 * time.Hour),
+				}
+
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertOne(context.Background(), futureMessage)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:          "should claim higher priority message before older ones",
+			wantMessageID: highPriorityID,
+			wantErr:       false,
+			wantStatus:    StatusProcessing,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				now := time.Now()
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertMany(context.Background(), []interface{}{
+					*newUnsentMessage("Spring sale starts today!", "+15553579024", PriorityLow, nil, now.Add(-2*time.Hour)),
+					*newUnsentMessage("Your order has shipped.", "+15553579025", PriorityNormal, nil, now.Add(-time.Hour)),
+					Message{ID: highPriorityID, Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579026", Status: StatusUnsent, Priority: PriorityHigh, PriorityRank: priorityRank(PriorityHigh), CreatedAt: now, ScheduledAt: now},
+				})
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:          "should only claim messages of the requested priority",
+			priority:      PriorityLow,
+			wantMessageID: lowPriorityID,
+			wantErr:       false,
+			wantStatus:    StatusProcessing,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				now := time.Now()
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertMany(context.Background(), []interface{}{
+					*newUnsentMessage("Your verification code is: 729384", "+15553579024", PriorityHigh, nil, now.Add(-time.Hour)),
+					Message{ID: lowPriorityID, Content: "Spring sale starts today!", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, Priority: PriorityLow, PriorityRank: priorityRank(PriorityLow), CreatedAt: now, ScheduledAt: now},
+				})
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:          "should not return message of a held campaign",
+			wantMessageID: primitive.NilObjectID,
+			wantErr:       true,
+			wantStatus:    "",
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				campaignID := primitive.NewObjectID()
+				heldMessage := Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Our store opens at 9am tomorrow.",
+					RecipientPhoneNumber: "+15553579024",
+					Status:               StatusUnsent,
+					CreatedAt:            time.Now(),
+					ScheduledAt:          time.Now(),
+					CampaignID:           &campaignID,
+					Held:                 true,
+				}
+
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertOne(context.Background(), heldMessage)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
 		{
 			name:          "should return error when decoding fails",
 			wantMessageID: primitive.NilObjectID,
@@ -253,11 +447,14 @@ func TestRepository_FetchAndMarkProcessing(t *testing.T) {
 			defer cleanFunc()
 
 			messageRepository := NewMessageRepositoryImpl(client.Database(testDB).Collection(testCollection))
-			gotData, err := messageRepository.FetchAndMarkProcessing(context.Background())
+			gotData, err := messageRepository.FetchAndMarkProcessing(context.Background(), "worker-1", tt.priority)
 			assert.Equal(t, tt.wantErr, err != nil)
 			if !tt.wantErr {
 				assert.Equal(t, tt.wantMessageID, gotData.ID)
 				assert.Equal(t, tt.wantStatus, gotData.Status)
+				assert.Equal(t, "worker-1", gotData.WorkerID)
+				assert.Equal(t, 1, gotData.Attempts)
+				assert.False(t, gotData.ProcessingStartedAt.IsZero())
 			}
 		})
 	}
@@ -416,7 +613,36 @@ func TestRepository_MarkAsFailed(t *testing.T) {
 				assert.NoError(t, err)
 
 				var bsonMessages []interface{}
-				for _, message := range sampleMixedMessages {
+				for i, message := range sampleMixedMessages {
+					if i == 0 {
+						message.Status = StatusProcessing
+						message.WorkerID = "worker-1"
+					}
+					bsonMessages = append(bsonMessages, message)
+				}
+
+				messageCollection := client.Database(testDB).Collection(testCollection)
+				_, err = messageCollection.InsertMany(context.Background(), bsonMessages)
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name:       "should return error when another worker holds the lease",
+			wantErr:    true,
+			wantStatus: "",
+			markID:     sampleMixedMessages[0].ID,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				var bsonMessages []interface{}
+				for i, message := range sampleMixedMessages {
+					if i == 0 {
+						message.Status = StatusProcessing
+						message.WorkerID = "worker-2"
+					}
 					bsonMessages = append(bsonMessages, message)
 				}
 
@@ -485,7 +711,7 @@ func TestRepository_MarkAsFailed(t *testing.T) {
 			defer cleanFunc()
 
 			messageRepository := NewMessageRepositoryImpl(client.Database(testDB).Collection(testCollection))
-			err := messageRepository.MarkAsFailed(context.Background(), tt.markID, "failed")
+			err := messageRepository.MarkAsFailed(context.Background(), &Message{ID: tt.markID, WorkerID: "worker-1"}, DeliveryFailure{Reason: "failed", Class: FailureClassPermanent, StatusCode: 400})
 			assert.Equal(t, tt.wantErr, err != nil)
 
 			if !tt.wantErr {
@@ -493,7 +719,960 @@ func TestRepository_MarkAsFailed(t *testing.T) {
 				err = client.Database(testDB).Collection(testCollection).FindOne(context.Background(), bson.M{"_id": tt.markID}).Decode(&updatedMessage)
 				assert.NoError(t, err)
 				assert.Equal(t, StatusFailed, updatedMessage.Status)
+				assert.Equal(t, FailureClassPermanent, updatedMessage.FailureClass)
+				assert.Equal(t, 400, updatedMessage.FailureStatusCode)
+			}
+		})
+	}
+}
+
+func TestRepository_InsertMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     *Message
+		wantErr     bool
+		beforeSuite func() (*mongo.Client, func())
+	}{
+		{
+			name: "should insert message and return generated ID",
+			message: &Message{
+				Content:              "Your verification code is: 729384",
+				RecipientPhoneNumber: "+15553579024",
+				Status:               StatusUnsent,
+				CreatedAt:            time.Date(2025, 5, 10, 8, 20, 0, 0, time.UTC),
+			},
+			wantErr: false,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+		{
+			name: "should return error when message ID already exists",
+			message: &Message{
+				ID:                   primitive.NewObjectID(),
+				Content:              "Duplicate message",
+				RecipientPhoneNumber: "+15553579024",
+				Status:               StatusUnsent,
+			},
+			wantErr: true,
+			beforeSuite: func() (*mongo.Client, func()) {
+				client, cleanFunc, err := prepareTestMongoStore()
+				assert.NoError(t, err)
+
+				return client, cleanFunc
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, cleanFunc := tt.beforeSuite()
+
+			defer client.Disconnect(context.Background())
+			defer cleanFunc()
+
+			messageCollection := client.Database(testDB).Collection(testCollection)
+			if tt.wantErr {
+				_, err := messageCollection.InsertOne(context.Background(), tt.message)
+				assert.NoError(t, err)
+			}
+
+			messageRepository := NewMessageRepositoryImpl(messageCollection)
+			gotID, err := messageRepository.InsertMessage(context.Background(), tt.message)
+			assert.Equal(t, tt.wantErr, err != nil)
+
+			if !tt.wantErr {
+				var insertedMessage Message
+				err = messageCollection.FindOne(context.Background(), bson.M{"_id": gotID}).Decode(&insertedMessage)
+				assert.NoError(t, err)
+				assert.Equal(t, StatusUnsent, insertedMessage.Status)
+				assert.Equal(t, tt.message.Content, insertedMessage.Content)
 			}
 		})
 	}
 }
+
+func TestRepository_ReleaseExpiredLeases(t *testing.T) {
+	now := time.Now()
+	expiredBefore := now.Add(-5 * time.Minute)
+
+	expiredUndispatched := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Expired lease, webhook not called",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusProcessing,
+		WorkerID:             "worker-1",
+		ProcessingStartedAt:  now.Add(-10 * time.Minute),
+		Attempts:             2,
+	}
+	expiredDispatched := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Expired lease, webhook maybe called",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusProcessing,
+		WorkerID:             "worker-1",
+		ProcessingStartedAt:  now.Add(-10 * time.Minute),
+		DispatchStartedAt:    now.Add(-9 * time.Minute),
+	}
+	activeLease := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Active lease",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusProcessing,
+		WorkerID:             "worker-2",
+		ProcessingStartedAt:  now.Add(-time.Minute),
+	}
+
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{expiredUndispatched, expiredDispatched, activeLease})
+	assert.NoError(t, err)
+
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+	released, err := messageRepository.ReleaseExpiredLeases(context.Background(), expiredBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), released)
+
+	var releasedMessage Message
+	err = messageCollection.FindOne(context.Background(), bson.M{"_id": expiredUndispatched.ID}).Decode(&releasedMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, releasedMessage.Attempts, "a reaped claim is not counted as an attempt")
+	assert.Empty(t, releasedMessage.WorkerID)
+
+	dispatches, err := messageRepository.RetrieveExpiredDispatches(context.Background(), expiredBefore)
+	assert.NoError(t, err)
+	if assert.Len(t, dispatches, 1) {
+		assert.Equal(t, expiredDispatched.ID, dispatches[0].ID)
+	}
+
+	wantStatuses := map[primitive.ObjectID]string{
+		expiredUndispatched.ID: StatusUnsent,
+		expiredDispatched.ID:   StatusProcessing,
+		activeLease.ID:         StatusProcessing,
+	}
+	for id, wantStatus := range wantStatuses {
+		var message Message
+		err = messageCollection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&message)
+		assert.NoError(t, err)
+		assert.Equal(t, wantStatus, message.Status)
+	}
+}
+
+func TestRepository_MarkAsStuck(t *testing.T) {
+	processing := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Lease expired after dispatch",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusProcessing,
+		WorkerID:             "worker-1",
+		ProcessingStartedAt:  time.Now().Add(-10 * time.Minute),
+		DispatchStartedAt:    time.Now().Add(-9 * time.Minute),
+	}
+	sent := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Sent while the reaper looked at it",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusSent,
+		WorkerID:             "worker-1",
+	}
+
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{processing, sent})
+	assert.NoError(t, err)
+
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	err = messageRepository.MarkAsStuck(context.Background(), &Message{ID: processing.ID, WorkerID: "worker-2"}, "stale lease")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	err = messageRepository.MarkAsStuck(context.Background(), &processing, "stale lease")
+	assert.NoError(t, err)
+
+	err = messageRepository.MarkAsStuck(context.Background(), &sent, "stale lease")
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	var stuck Message
+	err = messageCollection.FindOne(context.Background(), bson.M{"_id": processing.ID}).Decode(&stuck)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusStuck, stuck.Status)
+	assert.Equal(t, "stale lease", stuck.LastError)
+
+	var stillSent Message
+	err = messageCollection.FindOne(context.Background(), bson.M{"_id": sent.ID}).Decode(&stillSent)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSent, stillSent.Status)
+}
+
+func TestRepository_RequeueFailedMessages(t *testing.T) {
+	failedRetryable := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Failed with 503",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusFailed,
+		Attempts:             5,
+		LastError:            "failed to send webhook: failed to post message, status code: 503",
+		FailureClass:         FailureClassRetryable,
+		FailureStatusCode:    503,
+		FailedAt:             time.Now().Add(-time.Hour),
+	}
+	failedPermanent := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Failed with 400",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusFailed,
+		Attempts:             1,
+		FailureClass:         FailureClassPermanent,
+		FailureStatusCode:    400,
+		FailedAt:             time.Now().Add(-time.Hour),
+	}
+	campaignID := primitive.NewObjectID()
+	failedHeld := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Failed in a cancelled campaign",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusFailed,
+		CampaignID:           &campaignID,
+		Held:                 true,
+		FailureClass:         FailureClassRetryable,
+		FailureStatusCode:    503,
+		FailedAt:             time.Now().Add(-time.Hour),
+	}
+
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{failedRetryable, failedPermanent, failedHeld})
+	assert.NoError(t, err)
+
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	failedMessages, total, err := messageRepository.RetrieveFailedMessages(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, failedMessages, 3)
+
+	requeued, err := messageRepository.RequeueFailedMessages(context.Background(), RequeueFailedMessagesRequest{FailureClass: FailureClassRetryable})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), requeued)
+
+	var requeuedMessage Message
+	err = messageCollection.FindOne(context.Background(), bson.M{"_id": failedRetryable.ID}).Decode(&requeuedMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusUnsent, requeuedMessage.Status)
+	assert.Equal(t, 0, requeuedMessage.Attempts)
+	assert.Empty(t, requeuedMessage.FailureClass)
+
+	err = messageRepository.RequeueMessage(context.Background(), failedPermanent.ID)
+	assert.NoError(t, err)
+
+	err = messageRepository.RequeueMessage(context.Background(), failedPermanent.ID)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	err = messageRepository.RequeueMessage(context.Background(), failedHeld.ID)
+	assert.ErrorIs(t, err, ErrMessageHeld)
+
+	var heldMessage Message
+	err = messageCollection.FindOne(context.Background(), bson.M{"_id": failedHeld.ID}).Decode(&heldMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusFailed, heldMessage.Status)
+}
+
+func TestRepository_RequeueStuckMessage(t *testing.T) {
+	stuck := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Timed out",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusStuck,
+		Attempts:             2,
+		LastError:            "webhook call may have delivered the message: context deadline exceeded",
+		WorkerID:             "worker-1",
+		ProcessingStartedAt:  time.Now().Add(-time.Hour),
+		DispatchStartedAt:    time.Now().Add(-time.Hour),
+	}
+	campaignID := primitive.NewObjectID()
+	stuckHeld := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Stuck in a cancelled campaign",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusStuck,
+		CampaignID:           &campaignID,
+		Held:                 true,
+	}
+	failed := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Failed",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusFailed,
+	}
+
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{stuck, stuckHeld, failed})
+	assert.NoError(t, err)
+
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	err = messageRepository.RequeueStuckMessage(context.Background(), stuck.ID)
+	assert.NoError(t, err)
+
+	var requeuedMessage Message
+	err = messageCollection.FindOne(context.Background(), bson.M{"_id": stuck.ID}).Decode(&requeuedMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusUnsent, requeuedMessage.Status)
+	assert.Equal(t, 0, requeuedMessage.Attempts)
+	assert.Empty(t, requeuedMessage.LastError)
+	assert.Empty(t, requeuedMessage.WorkerID)
+	assert.True(t, requeuedMessage.DispatchStartedAt.IsZero())
+
+	err = messageRepository.RequeueStuckMessage(context.Background(), stuck.ID)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	err = messageRepository.RequeueStuckMessage(context.Background(), failed.ID)
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+
+	err = messageRepository.RequeueStuckMessage(context.Background(), stuckHeld.ID)
+	assert.ErrorIs(t, err, ErrMessageHeld)
+}
+
+func TestRepository_RecordAttempt(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	message := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Your verification code is: 729384",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusProcessing,
+	}
+	_, err = messageCollection.InsertOne(context.Background(), message)
+	assert.NoError(t, err)
+
+	attempts := []DeliveryAttempt{
+		{AttemptedAt: time.Date(2025, 5, 10, 8, 20, 0, 0, time.UTC), WorkerID: "worker-1", StatusCode: 503, LatencyMs: 120, Error: "failed to post message, status code: 503"},
+		{AttemptedAt: time.Date(2025, 5, 10, 8, 21, 0, 0, time.UTC), WorkerID: "worker-2", StatusCode: 202, LatencyMs: 80, ProviderMessageID: "webhook-message-id"},
+	}
+	for _, attempt := range attempts {
+		assert.NoError(t, messageRepository.RecordAttempt(context.Background(), message.ID, attempt))
+	}
+
+	got, err := messageRepository.RetrieveMessage(context.Background(), message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, attempts, got.History)
+
+	err = messageRepository.RecordAttempt(context.Background(), primitive.NewObjectID(), attempts[0])
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	_, err = messageRepository.RetrieveMessage(context.Background(), primitive.NewObjectID())
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
+
+func TestRepository_CancelAndUpdateMessage(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	unsent := Message{ID: primitive.NewObjectID(), Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent}
+	processing := Message{ID: primitive.NewObjectID(), Content: "Your verification code is: 118274", RecipientPhoneNumber: "+15553579025", Status: StatusProcessing}
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{unsent, processing})
+	assert.NoError(t, err)
+
+	content := "Your verification code is: 550912"
+	updated, err := messageRepository.UpdateMessage(context.Background(), unsent.ID, UpdateMessageRequest{Content: &content})
+	assert.NoError(t, err)
+	assert.Equal(t, content, updated.Content)
+	assert.Equal(t, unsent.RecipientPhoneNumber, updated.RecipientPhoneNumber)
+
+	_, err = messageRepository.UpdateMessage(context.Background(), processing.ID, UpdateMessageRequest{Content: &content})
+	assert.Equal(t, ErrMessageNotEditable, err)
+
+	assert.NoError(t, messageRepository.CancelMessage(context.Background(), unsent.ID))
+	cancelled, err := messageRepository.RetrieveMessage(context.Background(), unsent.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusCancelled, cancelled.Status)
+	assert.False(t, cancelled.CancelledAt.IsZero())
+
+	assert.Equal(t, ErrMessageNotEditable, messageRepository.CancelMessage(context.Background(), unsent.ID))
+	assert.Equal(t, ErrMessageNotEditable, messageRepository.CancelMessage(context.Background(), processing.ID))
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.CancelMessage(context.Background(), primitive.NewObjectID()))
+}
+
+func TestRepository_InsertMessages(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	existing := Message{ID: primitive.NewObjectID(), Content: "Existing", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent}
+	_, err = messageCollection.InsertOne(context.Background(), existing)
+	assert.NoError(t, err)
+
+	failed, err := messageRepository.InsertMessages(context.Background(), []Message{
+		{Content: "One", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent},
+		existing,
+		{Content: "Two", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, Metadata: map[string]string{"order": "42"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, failed, 1)
+	assert.Contains(t, failed[1], "duplicate key")
+
+	count, err := messageCollection.CountDocuments(context.Background(), bson.M{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestRepository_CampaignMessages(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	campaignID := primitive.NewObjectID()
+	otherCampaignID := primitive.NewObjectID()
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{
+		Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, CampaignID: &campaignID},
+		Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, CampaignID: &campaignID},
+		Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579026", Status: StatusSent, CampaignID: &campaignID},
+		Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579027", Status: StatusUnsent, CampaignID: &otherCampaignID},
+	})
+	assert.NoError(t, err)
+
+	counts, err := messageRepository.CountCampaignMessages(context.Background(), campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{StatusUnsent: 2, StatusSent: 1}, counts)
+
+	held, err := messageRepository.SetCampaignHold(context.Background(), campaignID, true)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), held)
+
+	heldCount, err := messageCollection.CountDocuments(context.Background(), bson.M{"held": true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), heldCount)
+
+	released, err := messageRepository.SetCampaignHold(context.Background(), campaignID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), released)
+
+	cancelled, err := messageRepository.CancelCampaignMessages(context.Background(), campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cancelled)
+
+	counts, err = messageRepository.CountCampaignMessages(context.Background(), campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{StatusCancelled: 2, StatusSent: 1}, counts)
+
+	counts, err = messageRepository.CountCampaignMessages(context.Background(), otherCampaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{StatusUnsent: 1}, counts)
+}
+
+func TestRepository_CancelledCampaignClaimedMessages(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	campaignID := primitive.NewObjectID()
+	expired := time.Now().Add(-time.Hour)
+	retried := Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579024", Status: StatusProcessing, WorkerID: "worker-1", ProcessingStartedAt: time.Now(), CampaignID: &campaignID}
+	deferred := Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579025", Status: StatusProcessing, WorkerID: "worker-1", ProcessingStartedAt: time.Now(), CampaignID: &campaignID}
+	abandoned := Message{ID: primitive.NewObjectID(), Content: "Sale", RecipientPhoneNumber: "+15553579026", Status: StatusProcessing, WorkerID: "worker-2", ProcessingStartedAt: expired, CampaignID: &campaignID}
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{retried, deferred, abandoned})
+	assert.NoError(t, err)
+
+	_, err = messageRepository.SetCampaignHold(context.Background(), campaignID, true)
+	assert.NoError(t, err)
+	cancelled, err := messageRepository.CancelCampaignMessages(context.Background(), campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), cancelled)
+
+	err = messageRepository.ScheduleRetry(context.Background(), &retried, time.Now().Add(time.Minute), DeliveryFailure{Reason: "timeout", Class: FailureClassRetryable})
+	assert.NoError(t, err)
+
+	err = messageRepository.DeferMessage(context.Background(), &deferred, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	released, err := messageRepository.ReleaseExpiredLeases(context.Background(), time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), released)
+
+	counts, err := messageRepository.CountCampaignMessages(context.Background(), campaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{StatusCancelled: 3}, counts)
+
+	err = messageRepository.ScheduleRetry(context.Background(), &retried, time.Now().Add(time.Minute), DeliveryFailure{Reason: "timeout", Class: FailureClassRetryable})
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+}
+
+func TestRepository_ExpireMessages(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	stale := Message{ID: primitive.NewObjectID(), Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, ExpiresAt: &past}
+	fresh := Message{ID: primitive.NewObjectID(), Content: "Your verification code is: 118274", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, ExpiresAt: &future}
+	noExpiry := Message{ID: primitive.NewObjectID(), Content: "Reminder", RecipientPhoneNumber: "+15553579026", Status: StatusUnsent}
+	claimed := Message{ID: primitive.NewObjectID(), Content: "Your verification code is: 550912", RecipientPhoneNumber: "+15553579027", Status: StatusProcessing, WorkerID: "worker-1", ExpiresAt: &past}
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{stale, fresh, noExpiry, claimed})
+	assert.NoError(t, err)
+
+	expired, err := messageRepository.ExpireMessages(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+
+	got, err := messageRepository.RetrieveMessage(context.Background(), stale.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusExpired, got.Status)
+	assert.False(t, got.ExpiredAt.IsZero())
+
+	assert.NoError(t, messageRepository.MarkAsExpired(context.Background(), &claimed))
+	got, err = messageRepository.RetrieveMessage(context.Background(), claimed.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusExpired, got.Status)
+
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.MarkAsExpired(context.Background(), &claimed))
+
+	for _, id := range []primitive.ObjectID{fresh.ID, noExpiry.ID} {
+		got, err := messageRepository.RetrieveMessage(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, StatusUnsent, got.Status)
+	}
+}
+
+func TestRepository_DeferMessage(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	message := Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Spring sale starts today!",
+		RecipientPhoneNumber: "+15553579024",
+		Status:               StatusProcessing,
+		WorkerID:             "worker-1",
+		ProcessingStartedAt:  time.Now(),
+		Attempts:             1,
+	}
+	_, err = messageCollection.InsertOne(context.Background(), message)
+	assert.NoError(t, err)
+
+	until := time.Now().Add(6 * time.Hour).Truncate(time.Millisecond)
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.DeferMessage(context.Background(), &Message{ID: message.ID, WorkerID: "worker-2"}, until))
+	assert.NoError(t, messageRepository.DeferMessage(context.Background(), &message, until))
+
+	got, err := messageRepository.RetrieveMessage(context.Background(), message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusUnsent, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+	assert.Equal(t, 1, got.Deferrals)
+	assert.True(t, until.Equal(got.DeferredUntil))
+	assert.True(t, until.Equal(got.NextAttemptAt))
+	assert.Empty(t, got.WorkerID)
+
+	// A second defer from the same worker no longer matches, so the claim is
+	// not given back twice.
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.DeferMessage(context.Background(), &message, until))
+	got, err = messageRepository.RetrieveMessage(context.Background(), message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got.Attempts)
+	assert.Equal(t, 1, got.Deferrals)
+}
+
+func TestRepository_MarkAsSuppressed(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	message := Message{ID: primitive.NewObjectID(), Content: "Spring sale starts today!", RecipientPhoneNumber: "+15553579024", Status: StatusProcessing, WorkerID: "worker-1"}
+	_, err = messageCollection.InsertOne(context.Background(), message)
+	assert.NoError(t, err)
+
+	assert.NoError(t, messageRepository.MarkAsSuppressed(context.Background(), &message, "recipient suppressed: opted out"))
+
+	got, err := messageRepository.RetrieveMessage(context.Background(), message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSuppressed, got.Status)
+	assert.Equal(t, "recipient suppressed: opted out", got.LastError)
+
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.MarkAsSuppressed(context.Background(), &message, "opted out"))
+}
+
+func TestRepository_MarkAsDuplicate(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	originalID := primitive.NewObjectID()
+	message := Message{ID: primitive.NewObjectID(), Content: "Spring sale starts today!", RecipientPhoneNumber: "+15553579024", Status: StatusProcessing, WorkerID: "worker-1"}
+	_, err = messageCollection.InsertOne(context.Background(), message)
+	assert.NoError(t, err)
+
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.MarkAsDuplicate(context.Background(), &Message{ID: message.ID, WorkerID: "worker-2"}, originalID))
+	assert.NoError(t, messageRepository.MarkAsDuplicate(context.Background(), &message, originalID))
+
+	got, err := messageRepository.RetrieveMessage(context.Background(), message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusDuplicate, got.Status)
+	assert.Equal(t, &originalID, got.DuplicateOf)
+
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.MarkAsDuplicate(context.Background(), &message, originalID))
+}
+
+func TestRepository_ListMessagesByReference(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageRepository := NewMessageRepositoryImpl(client.Database(testDB).Collection(testCollection))
+	assert.NoError(t, messageRepository.EnsureIndexes(context.Background()))
+
+	createdAt := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	shipped := Message{Content: "Your order has shipped", RecipientPhoneNumber: "+15553579024", Status: StatusSent, ExternalID: "order-42", Tags: []string{"shipping", "eu"}, CreatedAt: createdAt}
+	delivered := Message{Content: "Your order was delivered", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, ExternalID: "order-42", Tags: []string{"delivery"}, CreatedAt: createdAt.Add(time.Hour)}
+	other := Message{Content: "Your order has shipped", RecipientPhoneNumber: "+15553579025", Status: StatusSent, ExternalID: "order-43", Tags: []string{"shipping"}, CreatedAt: createdAt.Add(2 * time.Hour)}
+	for _, message := range []*Message{&shipped, &delivered, &other} {
+		message.ID, err = messageRepository.InsertMessage(context.Background(), message)
+		assert.NoError(t, err)
+	}
+
+	messages, _, err := messageRepository.ListMessages(context.Background(), MessageFilter{ExternalID: "order-42", Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, delivered.ID, messages[0].ID)
+		assert.Equal(t, shipped.ID, messages[1].ID)
+	}
+
+	messages, _, err = messageRepository.ListMessages(context.Background(), MessageFilter{Tag: "shipping", Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 2) {
+		assert.Equal(t, other.ID, messages[0].ID)
+		assert.Equal(t, shipped.ID, messages[1].ID)
+	}
+
+	messages, _, err = messageRepository.ListMessages(context.Background(), MessageFilter{ExternalID: "order-42", Tag: "shipping", Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, []string{"shipping", "eu"}, messages[0].Tags)
+	}
+}
+
+func TestRepository_MessageStats(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageRepository := NewMessageRepositoryImpl(client.Database(testDB).Collection(testCollection))
+
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	messages := []Message{
+		{Content: "One", RecipientPhoneNumber: "+15553579024", Status: StatusSent, CreatedAt: now.Add(-30 * time.Minute), SentAt: now.Add(-30*time.Minute + time.Second)},
+		{Content: "Two", RecipientPhoneNumber: "+15553579024", Status: StatusSent, CreatedAt: now.Add(-20 * time.Minute), SentAt: now.Add(-20*time.Minute + 3*time.Second)},
+		{Content: "Old", RecipientPhoneNumber: "+15553579024", Status: StatusSent, CreatedAt: now.Add(-3 * time.Hour), SentAt: now.Add(-3 * time.Hour)},
+		{Content: "Due", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, CreatedAt: now.Add(-time.Hour), ScheduledAt: now.Add(-10 * time.Minute)},
+		{Content: "Held", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, Held: true, CreatedAt: now.Add(-time.Hour), ScheduledAt: now.Add(-time.Hour)},
+		{Content: "Later", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, CreatedAt: now.Add(-time.Hour), ScheduledAt: now.Add(time.Hour)},
+		{Content: "Deferred", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, CreatedAt: now.Add(-time.Hour), ScheduledAt: now.Add(-time.Hour), NextAttemptAt: now.Add(time.Minute)},
+		{Content: "Retried", RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, CreatedAt: now.Add(-time.Hour), ScheduledAt: now.Add(-time.Hour), NextAttemptAt: now.Add(-5 * time.Minute)},
+		{Content: "Failed", RecipientPhoneNumber: "+15553579026", Status: StatusFailed, CreatedAt: now.Add(-time.Hour)},
+	}
+	_, err = messageRepository.InsertMessages(context.Background(), messages)
+	assert.NoError(t, err)
+
+	counts, err := messageRepository.CountMessagesByStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{StatusSent: 3, StatusUnsent: 5, StatusFailed: 1}, counts)
+
+	// "Deferred" waits for its next attempt and "Retried" became due again five
+	// minutes ago, so "Due" has been due the longest.
+	dueAt, err := messageRepository.RetrieveOldestDueTime(context.Background(), now)
+	assert.NoError(t, err)
+	assert.True(t, now.Add(-10*time.Minute).Equal(dueAt))
+
+	dueAt, err = messageRepository.RetrieveOldestDueTime(context.Background(), now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, now.Add(-10*time.Minute).Equal(dueAt))
+
+	_, err = messageRepository.RetrieveOldestDueTime(context.Background(), now.Add(-24*time.Hour))
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	sent, latency, err := messageRepository.SentMessageStats(context.Background(), now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), sent)
+	// Percentiles are approximate, so only their bounds are checked.
+	assert.GreaterOrEqual(t, latency.P50, int64(1000))
+	assert.LessOrEqual(t, latency.P50, latency.P95)
+	assert.LessOrEqual(t, latency.P95, latency.P99)
+	assert.LessOrEqual(t, latency.P99, int64(3000))
+
+	sent, latency, err = messageRepository.SentMessageStats(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), sent)
+	assert.Equal(t, LatencyPercentiles{}, latency)
+}
+
+func TestRepository_MessageStatsWithStringDates(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	collection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(collection)
+
+	// Documents inserted straight from sample/seed.json keep their timestamps
+	// as strings and have no scheduled_at.
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	_, err = collection.InsertMany(context.Background(), []interface{}{
+		bson.M{"content": "Seeded", "recipient_phone_number": "+15553579024", "status": StatusUnsent, "created_at": "2025-05-10T08:20:00Z", "sent_at": "0001-01-01T00:00:00Z"},
+		bson.M{"content": "Delivered", "recipient_phone_number": "+15553579025", "status": StatusSent, "created_at": "2025-05-10T11:30:00Z", "sent_at": now.Add(-30*time.Minute + 2*time.Second)},
+	})
+	assert.NoError(t, err)
+
+	dueAt, err := messageRepository.RetrieveOldestDueTime(context.Background(), now)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2025, 5, 10, 8, 20, 0, 0, time.UTC).Equal(dueAt))
+
+	sent, latency, err := messageRepository.SentMessageStats(context.Background(), now.Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), sent)
+	assert.Equal(t, int64(2000), latency.P99)
+}
+
+func TestRepository_EnsureIndexesBackfillsPriority(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	createdAt := time.Now().Add(-time.Hour)
+	legacyID := primitive.NewObjectID()
+	legacyLowID := primitive.NewObjectID()
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{
+		bson.M{"_id": legacyID, "content": "Stored before priorities", "recipient_phone_number": "+15553579024", "status": StatusUnsent, "created_at": createdAt, "scheduled_at": createdAt},
+		bson.M{"_id": legacyLowID, "content": "Low without a rank", "recipient_phone_number": "+15553579024", "status": StatusUnsent, "priority": PriorityLow, "created_at": createdAt, "scheduled_at": createdAt},
+	})
+	assert.NoError(t, err)
+
+	highID, err := messageRepository.InsertMessage(context.Background(), newUnsentMessage("Your verification code is: 729384", "+15553579025", PriorityHigh, nil, time.Now()))
+	assert.NoError(t, err)
+
+	assert.NoError(t, messageRepository.EnsureIndexes(context.Background()))
+
+	legacy, err := messageRepository.RetrieveMessage(context.Background(), legacyID)
+	assert.NoError(t, err)
+	assert.Equal(t, PriorityNormal, legacy.Priority)
+	assert.Equal(t, priorityRank(PriorityNormal), legacy.PriorityRank)
+
+	legacyLow, err := messageRepository.RetrieveMessage(context.Background(), legacyLowID)
+	assert.NoError(t, err)
+	assert.Equal(t, priorityRank(PriorityLow), legacyLow.PriorityRank)
+
+	// The high priority message is claimed first although it is the newest.
+	claimed, err := messageRepository.FetchAndMarkProcessing(context.Background(), "worker-1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, highID, claimed.ID)
+
+	claimed, err = messageRepository.FetchAndMarkProcessing(context.Background(), "worker-1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, legacyID, claimed.ID)
+}
+
+func TestRepository_EnsureIndexesRejectsDuplicateRecurringOccurrence(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageRepository := NewMessageRepositoryImpl(client.Database(testDB).Collection(testCollection))
+	assert.NoError(t, messageRepository.EnsureIndexes(context.Background()))
+
+	scheduleID := primitive.NewObjectID()
+	scheduledAt := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	occurrence := func() *Message {
+		return &Message{Content: "Daily reminder", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, ScheduledAt: scheduledAt, RecurringScheduleID: &scheduleID}
+	}
+
+	_, err = messageRepository.InsertMessage(context.Background(), occurrence())
+	assert.NoError(t, err)
+
+	_, err = messageRepository.InsertMessage(context.Background(), occurrence())
+	assert.True(t, mongo.IsDuplicateKeyError(err))
+
+	next := occurrence()
+	next.ScheduledAt = scheduledAt.Add(24 * time.Hour)
+	_, err = messageRepository.InsertMessage(context.Background(), next)
+	assert.NoError(t, err)
+
+	// Messages that do not belong to a recurring schedule are not constrained.
+	_, err = messageRepository.InsertMessage(context.Background(), &Message{Content: "One", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, ScheduledAt: scheduledAt})
+	assert.NoError(t, err)
+	_, err = messageRepository.InsertMessage(context.Background(), &Message{Content: "Two", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, ScheduledAt: scheduledAt})
+	assert.NoError(t, err)
+}
+
+func TestRepository_UpdateMessage(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	expiresAt := now.Add(2 * time.Hour)
+	templateID := primitive.NewObjectID()
+	retried := Message{ID: primitive.NewObjectID(), Content: "Spring sale starts today!", RecipientPhoneNumber: "+15553579024", Status: StatusUnsent, ScheduledAt: now, NextAttemptAt: now.Add(30 * time.Minute), DeferredUntil: now.Add(30 * time.Minute), ExpiresAt: &expiresAt}
+	templated := Message{ID: primitive.NewObjectID(), TemplateID: &templateID, RecipientPhoneNumber: "+15553579025", Status: StatusUnsent, ScheduledAt: now}
+	sent := Message{ID: primitive.NewObjectID(), Content: "Already delivered", RecipientPhoneNumber: "+15553579026", Status: StatusSent, ScheduledAt: now}
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{retried, templated, sent})
+	assert.NoError(t, err)
+
+	rescheduledAt := now.Add(time.Hour)
+	updated, err := messageRepository.UpdateMessage(context.Background(), retried.ID, UpdateMessageRequest{ScheduledAt: &rescheduledAt})
+	assert.NoError(t, err)
+	assert.True(t, rescheduledAt.Equal(updated.ScheduledAt))
+	assert.True(t, updated.NextAttemptAt.IsZero())
+	assert.True(t, updated.DeferredUntil.IsZero(), "a reschedule drops the deferral of the old schedule")
+
+	_, err = messageRepository.UpdateMessage(context.Background(), retried.ID, UpdateMessageRequest{ScheduledAt: &expiresAt})
+	assert.ErrorIs(t, err, ErrScheduledAfterExpiry)
+
+	content := "Your verification code is: 118274"
+	_, err = messageRepository.UpdateMessage(context.Background(), templated.ID, UpdateMessageRequest{Content: &content})
+	assert.ErrorIs(t, err, ErrTemplateContentNotEditable)
+
+	// Rescheduling a template message is still allowed.
+	_, err = messageRepository.UpdateMessage(context.Background(), templated.ID, UpdateMessageRequest{ScheduledAt: &rescheduledAt})
+	assert.NoError(t, err)
+
+	_, err = messageRepository.UpdateMessage(context.Background(), sent.ID, UpdateMessageRequest{Content: &content})
+	assert.ErrorIs(t, err, ErrMessageNotEditable)
+
+	_, err = messageRepository.UpdateMessage(context.Background(), primitive.NewObjectID(), UpdateMessageRequest{Content: &content})
+	assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+}
+
+func TestRepository_MarkAsDispatching(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	messageCollection := client.Database(testDB).Collection(testCollection)
+	messageRepository := NewMessageRepositoryImpl(messageCollection)
+
+	templateID := primitive.NewObjectID()
+	templated := Message{ID: primitive.NewObjectID(), TemplateID: &templateID, TemplateVariables: map[string]string{"code": "729384"}, RecipientPhoneNumber: "+15553579024", Status: StatusProcessing, WorkerID: "worker-1"}
+	plain := Message{ID: primitive.NewObjectID(), Content: "Spring sale starts today!", RecipientPhoneNumber: "+15553579025", Status: StatusProcessing, WorkerID: "worker-1"}
+	reclaimed := Message{ID: primitive.NewObjectID(), Content: "Flash sale ends tonight!", RecipientPhoneNumber: "+15553579026", Status: StatusProcessing, WorkerID: "worker-2"}
+	_, err = messageCollection.InsertMany(context.Background(), []interface{}{templated, plain, reclaimed})
+	assert.NoError(t, err)
+
+	// The worker renders the template in memory before dispatching.
+	templated.Content = "Your verification code is: 729384"
+	templated.Encoding, templated.Segments = MeasureSegments(templated.Content)
+	assert.NoError(t, messageRepository.MarkAsDispatching(context.Background(), &templated))
+	assert.NoError(t, messageRepository.MarkAsDispatching(context.Background(), &plain))
+
+	got, err := messageRepository.RetrieveMessage(context.Background(), templated.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Your verification code is: 729384", got.Content)
+	assert.Equal(t, EncodingGSM7, got.Encoding)
+	assert.Equal(t, 1, got.Segments)
+	assert.False(t, got.DispatchStartedAt.IsZero())
+
+	got, err = messageRepository.RetrieveMessage(context.Background(), plain.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Spring sale starts today!", got.Content)
+	assert.False(t, got.DispatchStartedAt.IsZero())
+
+	unclaimed := Message{ID: primitive.NewObjectID(), Status: StatusUnsent}
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.MarkAsDispatching(context.Background(), &unclaimed))
+
+	// worker-1's lease expired and worker-2 claimed the message again.
+	stale := reclaimed
+	stale.WorkerID = "worker-1"
+	assert.Equal(t, mongo.ErrNoDocuments, messageRepository.MarkAsDispatching(context.Background(), &stale))
+
+	got, err = messageRepository.RetrieveMessage(context.Background(), reclaimed.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "worker-2", got.WorkerID)
+	assert.True(t, got.DispatchStartedAt.IsZero())
+}
@@ -1,37 +1,535 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 var (
 	ErrDocumentNotFound    = errors.New("document not found")
 	ErrInternalServerError = errors.New("internal server error")
+	ErrValidationFailed    = errors.New("validation failed")
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
 )
 
 type MessageRepository interface {
-	RetrieveSentMessages() ([]Message, error)
+	ListMessages(ctx context.Context, filter MessageFilter) ([]Message, string, error)
+	RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error)
+	InsertMessage(ctx context.Context, message *Message) (primitive.ObjectID, error)
+	InsertMessages(ctx context.Context, messages []Message) (map[int]string, error)
+	CancelMessage(ctx context.Context, messageID primitive.ObjectID) error
+	UpdateMessage(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) (*Message, error)
+	RetrieveFailedMessages(ctx context.Context, page, limit int) ([]Message, int64, error)
+	RequeueMessage(ctx context.Context, messageID primitive.ObjectID) error
+	RequeueStuckMessage(ctx context.Context, messageID primitive.ObjectID) error
+	RequeueFailedMessages(ctx context.Context, filter RequeueFailedMessagesRequest) (int64, error)
+}
+
+type IdempotencyRepository interface {
+	ReserveKey(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)
+	ReleaseKey(ctx context.Context, key string) error
+}
+
+type MessageContentFilter interface {
+	Match(content string) (string, bool)
+}
+
+type MessageCache interface {
+	Del(ctx context.Context, key string) error
 }
 
 type MessageServiceImpl struct {
-	messageRepository MessageRepository
+	messageRepository     MessageRepository
+	idempotencyRepository IdempotencyRepository
+	contentFilter         MessageContentFilter
+	messageCache          MessageCache
+	config                Config
+	validate              *validator.Validate
 }
 
-func NewMessageServiceImpl(mr MessageRepository) *MessageServiceImpl {
+func NewMessageServiceImpl(mr MessageRepository, ir IdempotencyRepository, cf MessageContentFilter, mc MessageCache, cfg Config, validate *validator.Validate) *MessageServiceImpl {
 	return &MessageServiceImpl{
-		messageRepository: mr,
+		messageRepository:     mr,
+		idempotencyRepository: ir,
+		contentFilter:         cf,
+		messageCache:          mc,
+		config:                cfg,
+		validate:              validate,
 	}
 }
 
-func (ms *MessageServiceImpl) RetrieveSentMessages() ([]Message, error) {
-	sentMessages, err := ms.messageRepository.RetrieveSentMessages()
+func (ms *MessageServiceImpl) RetrieveSentMessages(ctx context.Context, limit int, pageToken string) (*MessagePage, error) {
+	sentMessages, err := ms.ListMessages(ctx, MessageFilter{
+		Status:    StatusSent,
+		SortBy:    "sent_at",
+		Limit:     limit,
+		PageToken: pageToken,
+	})
 	if err != nil {
-		return nil, ErrInternalServerError
+		return nil, err
 	}
 
-	if len(sentMessages) == 0 {
+	if len(sentMessages.Messages) == 0 {
 		return nil, ErrDocumentNotFound
 	}
 
 	return sentMessages, nil
 }
+
+func (ms *MessageServiceImpl) ListMessages(ctx context.Context, filter MessageFilter) (*MessagePage, error) {
+	if filter.RecipientPhoneNumber != "" {
+		normalized, err := NormalizePhoneNumber(filter.RecipientPhoneNumber, ms.config.Phone.DefaultRegion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: recipient must be a valid phone number", ErrValidationFailed)
+		}
+		filter.RecipientPhoneNumber = normalized
+	}
+
+	messages, nextPageToken, err := ms.messageRepository.ListMessages(ctx, filter)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPageToken) {
+			return nil, ErrInvalidPageToken
+		}
+		return nil, ErrInternalServerError
+	}
+
+	if messages == nil {
+		messages = []Message{}
+	}
+
+	return &MessagePage{
+		Messages:      messages,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (ms *MessageServiceImpl) RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error) {
+	message, err := ms.messageRepository.RetrieveMessage(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, ErrInternalServerError
+	}
+
+	return message, nil
+}
+
+// newUnsentMessage builds a message ready to be claimed by a worker. Without a
+// scheduled time the message is due immediately, and without a priority it has
+// normal priority.
+func newUnsentMessage(content, recipientPhoneNumber, priority string, scheduledAt *time.Time, now time.Time) *Message {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+
+	message := &Message{
+		Content:              content,
+		RecipientPhoneNumber: recipientPhoneNumber,
+		Status:               StatusUnsent,
+		Priority:             priority,
+		PriorityRank:         priorityRank(priority),
+		CreatedAt:            now,
+		ScheduledAt:          now,
+	}
+
+	if scheduledAt != nil {
+		message.ScheduledAt = *scheduledAt
+	}
+
+	if content != "" {
+		message.Encoding, message.Segments = MeasureSegments(content)
+	}
+
+	return message
+}
+
+// newRequestedMessage builds the message a CreateMessageRequest asks for.
+func newRequestedMessage(req CreateMessageRequest, now time.Time) (*Message, error) {
+	message := newUnsentMessage(req.Content, req.RecipientPhoneNumber, req.Priority, req.ScheduledAt, now)
+	message.ExpiresAt = req.ExpiresAt
+	message.DeliveryWindow = req.DeliveryWindow
+	message.ExternalID = req.ExternalID
+	message.Tags = req.Tags
+	message.Metadata = req.Metadata
+
+	templateID, err := parseMessageTemplateID(req.Content, req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if templateID != nil {
+		message.TemplateID = templateID
+		message.TemplateVariables = req.Variables
+	}
+
+	return message, nil
+}
+
+// ValidateMessage previews whether POST /messages would accept the request and
+// whether a worker would send the resulting message, without storing anything.
+// Template messages are only checked against the content filter once a worker
+// renders them.
+func (ms *MessageServiceImpl) ValidateMessage(req CreateMessageRequest) *MessageVerdict {
+	message, err := newRequestedMessage(req, time.Now())
+	if err != nil {
+		return &MessageVerdict{Reason: err.Error()}
+	}
+
+	if err := ms.validate.Struct(message); err != nil {
+		return &MessageVerdict{Reason: fmt.Sprintf("%s: %s", ErrValidationFailed, err.Error())}
+	}
+	if err := normalizePhoneNumberField(&message.RecipientPhoneNumber, ms.config.Phone.DefaultRegion); err != nil {
+		return &MessageVerdict{Reason: err.Error()}
+	}
+
+	verdict := &MessageVerdict{
+		RecipientPhoneNumber: message.RecipientPhoneNumber,
+		Encoding:             message.Encoding,
+		Segments:             message.Segments,
+	}
+
+	if rule, matched := ms.contentFilter.Match(message.Content); matched {
+		verdict.Rule = rule
+		verdict.Reason = contentFilterReason(rule)
+		return verdict
+	}
+
+	verdict.Valid = true
+	return verdict
+}
+
+// parseMessageTemplateID returns the template a message is rendered from, or
+// nil when the message carries its own content.
+func parseMessageTemplateID(content, templateID string) (*primitive.ObjectID, error) {
+	if templateID == "" {
+		return nil, nil
+	}
+
+	if content != "" {
+		return nil, fmt.Errorf("%w: content and template_id are mutually exclusive", ErrValidationFailed)
+	}
+
+	id, err := primitive.ObjectIDFromHex(templateID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidationFailed, ErrInvalidTemplateID.Error())
+	}
+
+	return &id, nil
+}
+
+func (ms *MessageServiceImpl) CreateMessage(ctx context.Context, req CreateMessageRequest) (primitive.ObjectID, error) {
+	message, err := newRequestedMessage(req, time.Now())
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	if err := ms.validate.Struct(message); err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+	if err := normalizePhoneNumberField(&message.RecipientPhoneNumber, ms.config.Phone.DefaultRegion); err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	if req.IdempotencyKey != "" {
+		return ms.createMessageOnce(ctx, req, message)
+	}
+
+	id, err := ms.messageRepository.InsertMessage(ctx, message)
+	if err != nil {
+		return primitive.NilObjectID, ErrInternalServerError
+	}
+
+	return id, nil
+}
+
+// createMessageOnce reserves the request's idempotency key before inserting the
+// message. The message ID is generated up front and stored with the key, so a
+// repeated request can be answered with the original ID.
+func (ms *MessageServiceImpl) createMessageOnce(ctx context.Context, req CreateMessageRequest, message *Message) (primitive.ObjectID, error) {
+	requestHash := hashCreateMessageRequest(req)
+	message.ID = primitive.NewObjectID()
+
+	existing, err := ms.idempotencyRepository.ReserveKey(ctx, &IdempotencyRecord{
+		Key:         req.IdempotencyKey,
+		RequestHash: requestHash,
+		MessageID:   message.ID,
+		CreatedAt:   message.CreatedAt,
+		ExpiresAt:   message.CreatedAt.Add(ms.config.Idempotency.Retention),
+	})
+	if err != nil {
+		return primitive.NilObjectID, ErrInternalServerError
+	}
+
+	if existing != nil {
+		if existing.RequestHash != requestHash {
+			return primitive.NilObjectID, ErrIdempotencyKeyReused
+		}
+
+		// The key is reserved before the message is inserted, so a missing
+		// message means the first request has not finished yet.
+		if _, err := ms.messageRepository.RetrieveMessage(ctx, existing.MessageID); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return primitive.NilObjectID, ErrIdempotencyKeyInProgress
+			}
+			return primitive.NilObjectID, ErrInternalServerError
+		}
+
+		return existing.MessageID, nil
+	}
+
+	id, err := ms.messageRepository.InsertMessage(ctx, message)
+	if err != nil {
+		_ = ms.idempotencyRepository.ReleaseKey(ctx, req.IdempotencyKey)
+		return primitive.NilObjectID, ErrInternalServerError
+	}
+
+	return id, nil
+}
+
+// ImportMessages validates every row of a bulk upload and inserts the valid ones
+// in batches. Invalid or failed rows are reported by line and never stop the
+// rest of the upload.
+func (ms *MessageServiceImpl) ImportMessages(ctx context.Context, format string, body io.Reader) (*BulkImportResponse, error) {
+	batchSize := max(ms.config.BulkImport.BatchSize, 1)
+	report := &BulkImportResponse{Errors: []BulkRowError{}}
+
+	var (
+		batch      []Message
+		batchLines []int
+		lastLine   int
+	)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		failed, err := ms.messageRepository.InsertMessages(ctx, batch)
+		for i, line := range batchLines {
+			switch {
+			case err != nil:
+				report.Errors = append(report.Errors, BulkRowError{Line: line, Error: "failed to insert message"})
+			case failed[i] != "":
+				report.Errors = append(report.Errors, BulkRowError{Line: line, Error: "failed to insert message: " + failed[i]})
+			default:
+				report.Inserted++
+			}
+		}
+
+		batch, batchLines = batch[:0], batchLines[:0]
+	}
+
+	readErr := readBulkRows(format, body, func(line int, row BulkMessageRow, rowErr error) {
+		report.Total++
+		lastLine = line
+
+		if rowErr != nil {
+			report.Errors = append(report.Errors, BulkRowError{Line: line, Error: rowErr.Error()})
+			return
+		}
+
+		message := newUnsentMessage(row.Content, row.RecipientPhoneNumber, row.Priority, row.ScheduledAt, time.Now())
+		message.ExpiresAt = row.ExpiresAt
+		message.ExternalID = row.ExternalID
+		message.Tags = row.Tags
+		message.Metadata = row.Metadata
+		if err := ms.validate.Struct(message); err != nil {
+			report.Errors = append(report.Errors, BulkRowError{Line: line, Error: fmt.Sprintf("%s: %s", ErrValidationFailed, err.Error())})
+			return
+		}
+		if err := normalizePhoneNumberField(&message.RecipientPhoneNumber, ms.config.Phone.DefaultRegion); err != nil {
+			report.Errors = append(report.Errors, BulkRowError{Line: line, Error: err.Error()})
+			return
+		}
+
+		batch = append(batch, *message)
+		batchLines = append(batchLines, line)
+		if len(batch) >= batchSize {
+			flush()
+		}
+	})
+
+	if errors.Is(readErr, ErrUnsupportedBulkFormat) || (errors.Is(readErr, ErrInvalidBulkUpload) && report.Total == 0) {
+		return nil, readErr
+	}
+
+	flush()
+
+	if readErr != nil {
+		report.Errors = append(report.Errors, BulkRowError{Line: lastLine + 1, Error: "upload aborted: " + readErr.Error()})
+	}
+
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+
+	return report, nil
+}
+
+func hashCreateMessageRequest(req CreateMessageRequest) string {
+	body, _ := json.Marshal(req) // the idempotency key itself is not part of the JSON
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func (ms *MessageServiceImpl) CancelMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	if err := ms.messageRepository.CancelMessage(ctx, messageID); err != nil {
+		return mapEditError(err)
+	}
+
+	return nil
+}
+
+func (ms *MessageServiceImpl) UpdateMessage(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) (*Message, error) {
+	var (
+		message Message
+		fields  []string
+	)
+
+	if req.Content != nil {
+		message.Content = *req.Content
+		fields = append(fields, "Content")
+	}
+	if req.RecipientPhoneNumber != nil {
+		message.RecipientPhoneNumber = *req.RecipientPhoneNumber
+		fields = append(fields, "RecipientPhoneNumber")
+	}
+	if req.ScheduledAt != nil {
+		fields = append(fields, "ScheduledAt")
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no fields to update", ErrValidationFailed)
+	}
+
+	if err := ms.validate.StructPartial(&message, fields...); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+	if req.RecipientPhoneNumber != nil {
+		if err := normalizePhoneNumberField(&message.RecipientPhoneNumber, ms.config.Phone.DefaultRegion); err != nil {
+			return nil, err
+		}
+		req.RecipientPhoneNumber = &message.RecipientPhoneNumber
+	}
+
+	updated, err := ms.messageRepository.UpdateMessage(ctx, messageID, req)
+	if err != nil {
+		if errors.Is(err, ErrScheduledAfterExpiry) || errors.Is(err, ErrTemplateContentNotEditable) {
+			return nil, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+		}
+		return nil, mapEditError(err)
+	}
+
+	return updated, nil
+}
+
+func mapEditError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrDocumentNotFound
+	}
+	if errors.Is(err, ErrMessageNotEditable) {
+		return ErrMessageNotEditable
+	}
+	return ErrInternalServerError
+}
+
+func (ms *MessageServiceImpl) RetrieveFailedMessages(ctx context.Context, page, limit int) (*FailedMessagesResponse, error) {
+	failedMessages, total, err := ms.messageRepository.RetrieveFailedMessages(ctx, page, limit)
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if failedMessages == nil {
+		failedMessages = []Message{}
+	}
+
+	return &FailedMessagesResponse{
+		Messages: failedMessages,
+		Page:     page,
+		Limit:    limit,
+		Total:    total,
+	}, nil
+}
+
+func (ms *MessageServiceImpl) RequeueMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	if err := ms.messageRepository.RequeueMessage(ctx, messageID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrDocumentNotFound
+		}
+		if errors.Is(err, ErrMessageHeld) {
+			return ErrMessageHeld
+		}
+		return ErrInternalServerError
+	}
+
+	return nil
+}
+
+// RequeueStuckMessage moves a stuck message back to unsent, for an operator who
+// checked that it was not delivered. The send guard left by the earlier attempt
+// is released first, as the worker would otherwise park the message in stuck
+// again. The worker still looks up the sent record before calling the webhook,
+// so a send that was recorded after all is reconciled instead of repeated.
+func (ms *MessageServiceImpl) RequeueStuckMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	message, err := ms.messageRepository.RetrieveMessage(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrDocumentNotFound
+		}
+		return ErrInternalServerError
+	}
+
+	// Only a stuck message's guard is stale; any other message may be in flight.
+	if message.Status != StatusStuck {
+		return ErrDocumentNotFound
+	}
+	if message.Held {
+		return ErrMessageHeld
+	}
+
+	if err := ms.messageCache.Del(ctx, sendGuardKey(messageID)); err != nil {
+		return ErrInternalServerError
+	}
+
+	if err := ms.messageRepository.RequeueStuckMessage(ctx, messageID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrDocumentNotFound
+		}
+		if errors.Is(err, ErrMessageHeld) {
+			return ErrMessageHeld
+		}
+		return ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (ms *MessageServiceImpl) RequeueFailedMessages(ctx context.Context, req RequeueFailedMessagesRequest) (int64, error) {
+	if req.RecipientPhoneNumber != "" {
+		normalized, err := NormalizePhoneNumber(req.RecipientPhoneNumber, ms.config.Phone.DefaultRegion)
+		if err != nil {
+			return 0, fmt.Errorf("%w: recipient_phone_number must be a valid phone number", ErrValidationFailed)
+		}
+		req.RecipientPhoneNumber = normalized
+	}
+
+	requeued, err := ms.messageRepository.RequeueFailedMessages(ctx, req)
+	if err != nil {
+		return 0, ErrInternalServerError
+	}
+
+	return requeued, nil
+}
@@ -10,8 +10,10 @@
 package main
 
 import (
+	context "context"
 	reflect "reflect"
 
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -39,17 +41,281 @@ func (m *MockMessageRepository) EXPECT() *MockMessageRepositoryMockRecorder {
 	return m.recorder
 }
 
-// RetrieveSentMessages mocks base method.
-func (m *MockMessageRepository) RetrieveSentMessages() ([]Message, error) {
+// CancelMessage mocks base method.
+func (m *MockMessageRepository) CancelMessage(ctx context.Context, messageID primitive.ObjectID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RetrieveSentMessages")
+	ret := m.ctrl.Call(m, "CancelMessage", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelMessage indicates an expected call of CancelMessage.
+func (mr *MockMessageRepositoryMockRecorder) CancelMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelMessage", reflect.TypeOf((*MockMessageRepository)(nil).CancelMessage), ctx, messageID)
+}
+
+// InsertMessage mocks base method.
+func (m *MockMessageRepository) InsertMessage(ctx context.Context, message *Message) (primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMessage", ctx, message)
+	ret0, _ := ret[0].(primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMessage indicates an expected call of InsertMessage.
+func (mr *MockMessageRepositoryMockRecorder) InsertMessage(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMessage", reflect.TypeOf((*MockMessageRepository)(nil).InsertMessage), ctx, message)
+}
+
+// InsertMessages mocks base method.
+func (m *MockMessageRepository) InsertMessages(ctx context.Context, messages []Message) (map[int]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMessages", ctx, messages)
+	ret0, _ := ret[0].(map[int]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMessages indicates an expected call of InsertMessages.
+func (mr *MockMessageRepositoryMockRecorder) InsertMessages(ctx, messages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMessages", reflect.TypeOf((*MockMessageRepository)(nil).InsertMessages), ctx, messages)
+}
+
+// ListMessages mocks base method.
+func (m *MockMessageRepository) ListMessages(ctx context.Context, filter MessageFilter) ([]Message, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", ctx, filter)
 	ret0, _ := ret[0].([]Message)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockMessageRepositoryMockRecorder) ListMessages(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockMessageRepository)(nil).ListMessages), ctx, filter)
+}
+
+// RequeueFailedMessages mocks base method.
+func (m *MockMessageRepository) RequeueFailedMessages(ctx context.Context, filter RequeueFailedMessagesRequest) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueFailedMessages", ctx, filter)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RetrieveSentMessages indicates an expected call of RetrieveSentMessages.
-func (mr *MockMessageRepositoryMockRecorder) RetrieveSentMessages() *gomock.Call {
+// RequeueFailedMessages indicates an expected call of RequeueFailedMessages.
+func (mr *MockMessageRepositoryMockRecorder) RequeueFailedMessages(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueFailedMessages", reflect.TypeOf((*MockMessageRepository)(nil).RequeueFailedMessages), ctx, filter)
+}
+
+// RequeueMessage mocks base method.
+func (m *MockMessageRepository) RequeueMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueMessage", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueMessage indicates an expected call of RequeueMessage.
+func (mr *MockMessageRepositoryMockRecorder) RequeueMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueMessage", reflect.TypeOf((*MockMessageRepository)(nil).RequeueMessage), ctx, messageID)
+}
+
+// RequeueStuckMessage mocks base method.
+func (m *MockMessageRepository) RequeueStuckMessage(ctx context.Context, messageID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueStuckMessage", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueStuckMessage indicates an expected call of RequeueStuckMessage.
+func (mr *MockMessageRepositoryMockRecorder) RequeueStuckMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueStuckMessage", reflect.TypeOf((*MockMessageRepository)(nil).RequeueStuckMessage), ctx, messageID)
+}
+
+// RetrieveFailedMessages mocks base method.
+func (m *MockMessageRepository) RetrieveFailedMessages(ctx context.Context, page, limit int) ([]Message, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveFailedMessages", ctx, page, limit)
+	ret0, _ := ret[0].([]Message)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RetrieveFailedMessages indicates an expected call of RetrieveFailedMessages.
+func (mr *MockMessageRepositoryMockRecorder) RetrieveFailedMessages(ctx, page, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveFailedMessages", reflect.TypeOf((*MockMessageRepository)(nil).RetrieveFailedMessages), ctx, page, limit)
+}
+
+// RetrieveMessage mocks base method.
+func (m *MockMessageRepository) RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveMessage", ctx, messageID)
+	ret0, _ := ret[0].(*Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveMessage indicates an expected call of RetrieveMessage.
+func (mr *MockMessageRepositoryMockRecorder) RetrieveMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveMessage", reflect.TypeOf((*MockMessageRepository)(nil).RetrieveMessage), ctx, messageID)
+}
+
+// UpdateMessage mocks base method.
+func (m *MockMessageRepository) UpdateMessage(ctx context.Context, messageID primitive.ObjectID, req UpdateMessageRequest) (*Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, messageID, req)
+	ret0, _ := ret[0].(*Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockMessageRepositoryMockRecorder) UpdateMessage(ctx, messageID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockMessageRepository)(nil).UpdateMessage), ctx, messageID, req)
+}
+
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ReleaseKey mocks base method.
+func (m *MockIdempotencyRepository) ReleaseKey(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseKey indicates an expected call of ReleaseKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) ReleaseKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ReleaseKey), ctx, key)
+}
+
+// ReserveKey mocks base method.
+func (m *MockIdempotencyRepository) ReserveKey(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveKey", ctx, record)
+	ret0, _ := ret[0].(*IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveKey indicates an expected call of ReserveKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) ReserveKey(ctx, record any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ReserveKey), ctx, record)
+}
+
+// MockMessageContentFilter is a mock of MessageContentFilter interface.
+type MockMessageContentFilter struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageContentFilterMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageContentFilterMockRecorder is the mock recorder for MockMessageContentFilter.
+type MockMessageContentFilterMockRecorder struct {
+	mock *MockMessageContentFilter
+}
+
+// NewMockMessageContentFilter creates a new mock instance.
+func NewMockMessageContentFilter(ctrl *gomock.Controller) *MockMessageContentFilter {
+	mock := &MockMessageContentFilter{ctrl: ctrl}
+	mock.recorder = &MockMessageContentFilterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageContentFilter) EXPECT() *MockMessageContentFilterMockRecorder {
+	return m.recorder
+}
+
+// Match mocks base method.
+func (m *MockMessageContentFilter) Match(content string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", content)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockMessageContentFilterMockRecorder) Match(content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockMessageContentFilter)(nil).Match), content)
+}
+
+// MockMessageCache is a mock of MessageCache interface.
+type MockMessageCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageCacheMockRecorder
+	isgomock struct{}
+}
+
+// MockMessageCacheMockRecorder is the mock recorder for MockMessageCache.
+type MockMessageCacheMockRecorder struct {
+	mock *MockMessageCache
+}
+
+// NewMockMessageCache creates a new mock instance.
+func NewMockMessageCache(ctrl *gomock.Controller) *MockMessageCache {
+	mock := &MockMessageCache{ctrl: ctrl}
+	mock.recorder = &MockMessageCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageCache) EXPECT() *MockMessageCacheMockRecorder {
+	return m.recorder
+}
+
+// Del mocks base method.
+func (m *MockMessageCache) Del(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockMessageCacheMockRecorder) Del(ctx, key any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveSentMessages", reflect.TypeOf((*MockMessageRepository)(nil).RetrieveSentMessages))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockMessageCache)(nil).Del), ctx, key)
 }
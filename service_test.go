@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -14,7 +19,7 @@ func TestService_RetrieveSentMessages(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := NewMockMessageRepository(ctrl)
-	mockService := NewMessageServiceImpl(mockRepo)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, Idempotency: IdempotencyConfig{Retention: time.Hour}}, newTestValidator())
 
 	sampleSentMessagesFilePath := "sample/sent_messages.json"
 	sampleSentMessageContentRawByte, err := os.ReadFile(sampleSentMessagesFilePath)
@@ -29,18 +34,20 @@ func TestService_RetrieveSentMessages(t *testing.T) {
 		return
 	}
 
+	sentFilter := MessageFilter{Status: StatusSent, SortBy: "sent_at", Limit: 20}
+
 	tests := []struct {
 		name        string
-		wantData    []Message
+		wantData    *MessagePage
 		wantErr     error
 		beforeSuite func()
 	}{
 		{
 			name:     "should return retrived sent messages",
-			wantData: sampleSentMessages,
+			wantData: &MessagePage{Messages: sampleSentMessages, NextPageToken: "next-token"},
 			wantErr:  nil,
 			beforeSuite: func() {
-				mockRepo.EXPECT().RetrieveSentMessages().Return(sampleSentMessages, nil)
+				mockRepo.EXPECT().ListMessages(gomock.Any(), sentFilter).Return(sampleSentMessages, "next-token", nil)
 			},
 		},
 		{
@@ -48,7 +55,15 @@ func TestService_RetrieveSentMessages(t *testing.T) {
 			wantData: nil,
 			wantErr:  ErrDocumentNotFound,
 			beforeSuite: func() {
-				mockRepo.EXPECT().RetrieveSentMessages().Return([]Message{}, nil)
+				mockRepo.EXPECT().ListMessages(gomock.Any(), sentFilter).Return([]Message{}, "", nil)
+			},
+		},
+		{
+			name:     "should return error when page token is invalid",
+			wantData: nil,
+			wantErr:  ErrInvalidPageToken,
+			beforeSuite: func() {
+				mockRepo.EXPECT().ListMessages(gomock.Any(), sentFilter).Return(nil, "", ErrInvalidPageToken)
 			},
 		},
 		{
@@ -56,7 +71,7 @@ func TestService_RetrieveSentMessages(t *testing.T) {
 			wantData: nil,
 			wantErr:  ErrInternalServerError,
 			beforeSuite: func() {
-				mockRepo.EXPECT().RetrieveSentMessages().Return(nil, ErrInternalServerError)
+				mockRepo.EXPECT().ListMessages(gomock.Any(), sentFilter).Return(nil, "", ErrInternalServerError)
 			},
 		},
 	}
@@ -64,9 +79,729 @@ func TestService_RetrieveSentMessages(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.beforeSuite()
-			got, err := mockService.RetrieveSentMessages()
+			got, err := mockService.RetrieveSentMessages(context.Background(), 20, "")
 			assert.Equal(t, tt.wantErr, err)
 			assert.Equal(t, tt.wantData, got)
 		})
 	}
 }
+
+func TestService_CreateMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockIdempotencyRepo := NewMockIdempotencyRepository(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, mockIdempotencyRepo, NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, Idempotency: IdempotencyConfig{Retention: time.Hour}}, newTestValidator())
+
+	createdID := primitive.NewObjectID()
+	scheduledAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	expiresBeforeSchedule := scheduledAt.Add(-time.Minute)
+	expiresAfterSchedule := scheduledAt.Add(time.Hour)
+	templateID := primitive.NewObjectID()
+	idempotentRequest := CreateMessageRequest{Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024", IdempotencyKey: "order-42"}
+	idempotentRequestHash := hashCreateMessageRequest(idempotentRequest)
+
+	tests := []struct {
+		name        string
+		req         CreateMessageRequest
+		wantID      primitive.ObjectID
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should create message with unsent status",
+			req:     CreateMessageRequest{Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024"},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Equal(t, StatusUnsent, message.Status)
+					assert.Equal(t, PriorityNormal, message.Priority)
+					assert.Equal(t, "Your verification code is: 729384", message.Content)
+					assert.Equal(t, EncodingGSM7, message.Encoding)
+					assert.Equal(t, 1, message.Segments)
+					assert.Equal(t, "+15553579024", message.RecipientPhoneNumber)
+					assert.False(t, message.CreatedAt.IsZero())
+					assert.Equal(t, message.CreatedAt, message.ScheduledAt)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:    "should keep requested scheduled and expiry time",
+			req:     CreateMessageRequest{Content: "Reminder", RecipientPhoneNumber: "+15553579024", ScheduledAt: &scheduledAt, ExpiresAt: &expiresAfterSchedule},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Equal(t, scheduledAt, message.ScheduledAt)
+					assert.Equal(t, &expiresAfterSchedule, message.ExpiresAt)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:    "should keep requested priority",
+			req:     CreateMessageRequest{Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024", Priority: PriorityHigh},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Equal(t, PriorityHigh, message.Priority)
+					assert.Equal(t, priorityRank(PriorityHigh), message.PriorityRank)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:    "should keep external ID, tags and metadata",
+			req:     CreateMessageRequest{Content: "Your order has shipped", RecipientPhoneNumber: "+15553579024", ExternalID: "order-42", Tags: []string{"shipping"}, Metadata: map[string]string{"store": "istanbul"}},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Equal(t, "order-42", message.ExternalID)
+					assert.Equal(t, []string{"shipping"}, message.Tags)
+					assert.Equal(t, map[string]string{"store": "istanbul"}, message.Metadata)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:        "should return validation error when tags repeat",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "+15553579024", Tags: []string{"shipping", "shipping"}},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when metadata key contains a dot",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "+15553579024", Metadata: map[string]string{"order.id": "42"}},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when metadata value is too long",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "+15553579024", Metadata: map[string]string{"note": strings.Repeat("a", 501)}},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when message expires before it is scheduled",
+			req:         CreateMessageRequest{Content: "Your verification code is: 729384", RecipientPhoneNumber: "+15553579024", ScheduledAt: &scheduledAt, ExpiresAt: &expiresBeforeSchedule},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when delivery window is invalid",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "+15553579024", DeliveryWindow: &DeliveryWindow{Timezone: "Mars/Olympus", Start: "09:00", End: "21:00"}},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when priority is unknown",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "+15553579024", Priority: "urgent"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when content is empty",
+			req:         CreateMessageRequest{Content: "", RecipientPhoneNumber: "+15553579024"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when recipient is invalid",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "not-a-number"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should return error when repository fails",
+			req:     CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "+15553579024"},
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, assert.AnError)
+			},
+		},
+		{
+			name:    "should create template message without content",
+			req:     CreateMessageRequest{TemplateID: templateID.Hex(), Variables: map[string]string{"code": "729384"}, RecipientPhoneNumber: "+15553579024"},
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Empty(t, message.Content)
+					assert.Equal(t, &templateID, message.TemplateID)
+					assert.Equal(t, map[string]string{"code": "729384"}, message.TemplateVariables)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:        "should return validation error when content and template are both given",
+			req:         CreateMessageRequest{Content: "Hello", TemplateID: templateID.Hex(), RecipientPhoneNumber: "+15553579024"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when template ID is invalid",
+			req:         CreateMessageRequest{TemplateID: "not-an-id", RecipientPhoneNumber: "+15553579024"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should reserve idempotency key with the pre-generated message ID",
+			req:     idempotentRequest,
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				var reservedID primitive.ObjectID
+				mockIdempotencyRepo.EXPECT().ReserveKey(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error) {
+					assert.Equal(t, "order-42", record.Key)
+					assert.Equal(t, idempotentRequestHash, record.RequestHash)
+					assert.Equal(t, time.Hour, record.ExpiresAt.Sub(record.CreatedAt))
+					reservedID = record.MessageID
+					return nil, nil
+				})
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, message *Message) (primitive.ObjectID, error) {
+					assert.Equal(t, reservedID, message.ID)
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:    "should return original message ID for repeated idempotent request",
+			req:     idempotentRequest,
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockIdempotencyRepo.EXPECT().ReserveKey(gomock.Any(), gomock.Any()).Return(&IdempotencyRecord{Key: "order-42", RequestHash: idempotentRequestHash, MessageID: createdID}, nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), createdID).Return(&Message{ID: createdID}, nil)
+			},
+		},
+		{
+			name:    "should reject reused idempotency key with a different request",
+			req:     idempotentRequest,
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrIdempotencyKeyReused,
+			beforeSuite: func() {
+				mockIdempotencyRepo.EXPECT().ReserveKey(gomock.Any(), gomock.Any()).Return(&IdempotencyRecord{Key: "order-42", RequestHash: "other-hash", MessageID: createdID}, nil)
+			},
+		},
+		{
+			name:    "should return in progress error when original request has not finished",
+			req:     idempotentRequest,
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrIdempotencyKeyInProgress,
+			beforeSuite: func() {
+				mockIdempotencyRepo.EXPECT().ReserveKey(gomock.Any(), gomock.Any()).Return(&IdempotencyRecord{Key: "order-42", RequestHash: idempotentRequestHash, MessageID: createdID}, nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), createdID).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should release idempotency key when insert fails",
+			req:     idempotentRequest,
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockIdempotencyRepo.EXPECT().ReserveKey(gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockRepo.EXPECT().InsertMessage(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, assert.AnError)
+				mockIdempotencyRepo.EXPECT().ReleaseKey(gomock.Any(), "order-42").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := mockService.CreateMessage(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantID, got)
+		})
+	}
+}
+
+func TestService_RequeueMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, Idempotency: IdempotencyConfig{Retention: time.Hour}}, newTestValidator())
+
+	messageID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should requeue failed message",
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(nil)
+			},
+		},
+		{
+			name:    "should return not found when message is missing or not failed",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return held error when message belongs to a paused or cancelled campaign",
+			wantErr: ErrMessageHeld,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(ErrMessageHeld)
+			},
+		},
+		{
+			name:    "should return internal error when repository fails",
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RequeueMessage(gomock.Any(), messageID).Return(assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			err := mockService.RequeueMessage(context.Background(), messageID)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_RequeueStuckMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockCache := NewMockMessageCache(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), mockCache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	messageID := primitive.NewObjectID()
+	campaignID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should release send guard and requeue stuck message",
+			wantErr: nil,
+			beforeSuite: func() {
+				gomock.InOrder(
+					mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(&Message{ID: messageID, Status: StatusStuck}, nil),
+					mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(messageID)).Return(nil),
+					mockRepo.EXPECT().RequeueStuckMessage(gomock.Any(), messageID).Return(nil),
+				)
+			},
+		},
+		{
+			name:    "should return not found when message is missing",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return not found and keep send guard when message is not stuck",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(&Message{ID: messageID, Status: StatusProcessing}, nil)
+			},
+		},
+		{
+			name:    "should return held error when message belongs to a paused or cancelled campaign",
+			wantErr: ErrMessageHeld,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(&Message{ID: messageID, Status: StatusStuck, CampaignID: &campaignID, Held: true}, nil)
+			},
+		},
+		{
+			name:    "should return internal error and keep message stuck when send guard cannot be released",
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(&Message{ID: messageID, Status: StatusStuck}, nil)
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(messageID)).Return(assert.AnError)
+			},
+		},
+		{
+			name:    "should return not found when message was requeued concurrently",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(&Message{ID: messageID, Status: StatusStuck}, nil)
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(messageID)).Return(nil)
+				mockRepo.EXPECT().RequeueStuckMessage(gomock.Any(), messageID).Return(mongo.ErrNoDocuments)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			err := mockService.RequeueStuckMessage(context.Background(), messageID)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_RetrieveMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, Idempotency: IdempotencyConfig{Retention: time.Hour}}, newTestValidator())
+
+	messageID := primitive.NewObjectID()
+	message := &Message{ID: messageID, Status: StatusSent}
+
+	tests := []struct {
+		name        string
+		wantData    *Message
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:     "should return message",
+			wantData: message,
+			wantErr:  nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(message, nil)
+			},
+		},
+		{
+			name:     "should return not found when message is missing",
+			wantData: nil,
+			wantErr:  ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:     "should return internal error when repository fails",
+			wantData: nil,
+			wantErr:  ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), messageID).Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			gotData, err := mockService.RetrieveMessage(context.Background(), messageID)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantData, gotData)
+		})
+	}
+}
+
+func TestService_UpdateMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, Idempotency: IdempotencyConfig{Retention: time.Hour}}, newTestValidator())
+
+	messageID := primitive.NewObjectID()
+	content := "Your verification code is: 118274"
+	tooLong := strings.Repeat("a", 161)
+	invalidRecipient := "12345"
+	nationalRecipient := "0555 111 22 33"
+	normalizedRecipient := "+905551112233"
+	scheduledAt := time.Date(2025, 5, 12, 9, 0, 0, 0, time.UTC)
+	updated := &Message{ID: messageID, Content: content, Status: StatusUnsent}
+
+	tests := []struct {
+		name        string
+		req         UpdateMessageRequest
+		wantData    *Message
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:     "should update message",
+			req:      UpdateMessageRequest{Content: &content, ScheduledAt: &scheduledAt},
+			wantData: updated,
+			wantErr:  nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpdateMessage(gomock.Any(), messageID, UpdateMessageRequest{Content: &content, ScheduledAt: &scheduledAt}).Return(updated, nil)
+			},
+		},
+		{
+			name:     "should store the normalized recipient",
+			req:      UpdateMessageRequest{RecipientPhoneNumber: &nationalRecipient},
+			wantData: updated,
+			wantErr:  nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpdateMessage(gomock.Any(), messageID, UpdateMessageRequest{RecipientPhoneNumber: &normalizedRecipient}).Return(updated, nil)
+			},
+		},
+		{
+			name:        "should return validation error when no field is given",
+			req:         UpdateMessageRequest{},
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when content is too long",
+			req:         UpdateMessageRequest{Content: &tooLong},
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return validation error when recipient is invalid",
+			req:         UpdateMessageRequest{RecipientPhoneNumber: &invalidRecipient},
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should return not found when message is missing",
+			req:     UpdateMessageRequest{Content: &content},
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpdateMessage(gomock.Any(), messageID, gomock.Any()).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return conflict error when message is no longer unsent",
+			req:     UpdateMessageRequest{Content: &content},
+			wantErr: ErrMessageNotEditable,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpdateMessage(gomock.Any(), messageID, gomock.Any()).Return(nil, ErrMessageNotEditable)
+			},
+		},
+		{
+			name:    "should return validation error when rescheduled at or after expiry",
+			req:     UpdateMessageRequest{ScheduledAt: &scheduledAt},
+			wantErr: ErrValidationFailed,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpdateMessage(gomock.Any(), messageID, gomock.Any()).Return(nil, ErrScheduledAfterExpiry)
+			},
+		},
+		{
+			name:    "should return validation error when content of a template message is edited",
+			req:     UpdateMessageRequest{Content: &content},
+			wantErr: ErrValidationFailed,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpdateMessage(gomock.Any(), messageID, gomock.Any()).Return(nil, ErrTemplateContentNotEditable)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			gotData, err := mockService.UpdateMessage(context.Background(), messageID, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantData, gotData)
+		})
+	}
+}
+
+func TestService_CancelMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, Idempotency: IdempotencyConfig{Retention: time.Hour}}, newTestValidator())
+
+	messageID := primitive.NewObjectID()
+
+	tests := []struct {
+		name        string
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should cancel message",
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().CancelMessage(gomock.Any(), messageID).Return(nil)
+			},
+		},
+		{
+			name:    "should return not found when message is missing",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().CancelMessage(gomock.Any(), messageID).Return(mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return conflict error when message is no longer unsent",
+			wantErr: ErrMessageNotEditable,
+			beforeSuite: func() {
+				mockRepo.EXPECT().CancelMessage(gomock.Any(), messageID).Return(ErrMessageNotEditable)
+			},
+		},
+		{
+			name:    "should return internal error when repository fails",
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().CancelMessage(gomock.Any(), messageID).Return(assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			err := mockService.CancelMessage(context.Background(), messageID)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestService_ImportMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockMessageRepository(ctrl)
+	mockService := NewMessageServiceImpl(mockRepo, NewMockIdempotencyRepository(ctrl), NewMockMessageContentFilter(ctrl), NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, BulkImport: BulkImportConfig{BatchSize: 2}}, newTestValidator())
+
+	tests := []struct {
+		name        string
+		format      string
+		body        string
+		wantReport  *BulkImportResponse
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:   "should insert valid rows in batches and report invalid rows",
+			format: BulkFormatNDJSON,
+			body: `{"content":"One","recipient_phone_number":"+15553579024","metadata":{"order":"1"}}
+{"content":"","recipient_phone_number":"+15553579024"}
+{"content":"Two","recipient_phone_number":"+15553579025"}
+{"content":"Three","recipient_phone_number":"+15553579026"}
+`,
+			wantReport: &BulkImportResponse{
+				Total:    4,
+				Inserted: 3,
+				Errors: []BulkRowError{
+					{Line: 2, Error: "validation failed: Key: 'Message.Content' Error:Field validation for 'Content' failed on the 'required_without' tag"},
+				},
+			},
+			beforeSuite: func() {
+				gomock.InOrder(
+					mockRepo.EXPECT().InsertMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, messages []Message) (map[int]string, error) {
+						assert.Len(t, messages, 2)
+						assert.Equal(t, "One", messages[0].Content)
+						assert.Equal(t, map[string]string{"order": "1"}, messages[0].Metadata)
+						assert.Equal(t, StatusUnsent, messages[0].Status)
+						assert.Equal(t, "Two", messages[1].Content)
+						return nil, nil
+					}),
+					mockRepo.EXPECT().InsertMessages(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, messages []Message) (map[int]string, error) {
+						assert.Len(t, messages, 1)
+						assert.Equal(t, "Three", messages[0].Content)
+						return nil, nil
+					}),
+				)
+			},
+		},
+		{
+			name:   "should report rows of a failed batch",
+			format: BulkFormatCSV,
+			body: `content,recipient_phone_number
+One,+15553579024
+Two,+15553579025
+`,
+			wantReport: &BulkImportResponse{
+				Total:    2,
+				Inserted: 1,
+				Errors: []BulkRowError{
+					{Line: 3, Error: "failed to insert message: duplicate key"},
+				},
+			},
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertMessages(gomock.Any(), gomock.Any()).Return(map[int]string{1: "duplicate key"}, nil)
+			},
+		},
+		{
+			name:        "should return error when CSV header is invalid",
+			format:      BulkFormatCSV,
+			body:        "content\nHello\n",
+			wantErr:     ErrInvalidBulkUpload,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			gotReport, err := mockService.ImportMessages(context.Background(), tt.format, strings.NewReader(tt.body))
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantReport, gotReport)
+		})
+	}
+}
+
+func TestService_ValidateMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockContentFilter := NewMockMessageContentFilter(ctrl)
+	mockService := NewMessageServiceImpl(NewMockMessageRepository(ctrl), NewMockIdempotencyRepository(ctrl), mockContentFilter, NewMockMessageCache(ctrl), Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	tests := []struct {
+		name        string
+		req         CreateMessageRequest
+		want        *MessageVerdict
+		beforeSuite func()
+	}{
+		{
+			name: "should accept a valid message",
+			req:  CreateMessageRequest{Content: "Doğrulama kodunuz: 729384", RecipientPhoneNumber: "0555 111 22 33"},
+			want: &MessageVerdict{Valid: true, RecipientPhoneNumber: "+905551112233", Encoding: EncodingUCS2, Segments: 1},
+			beforeSuite: func() {
+				mockContentFilter.EXPECT().Match("Doğrulama kodunuz: 729384").Return("", false)
+			},
+		},
+		{
+			name: "should report the content filter rule the content matched",
+			req:  CreateMessageRequest{Content: "See http://wiki.internal", RecipientPhoneNumber: "+15553579024"},
+			want: &MessageVerdict{
+				Reason:               "content matched filter rule internal-url",
+				Rule:                 "internal-url",
+				RecipientPhoneNumber: "+15553579024",
+				Encoding:             EncodingGSM7,
+				Segments:             1,
+			},
+			beforeSuite: func() {
+				mockContentFilter.EXPECT().Match("See http://wiki.internal").Return("internal-url", true)
+			},
+		},
+		{
+			name:        "should report validation errors",
+			req:         CreateMessageRequest{Content: "Hello", RecipientPhoneNumber: "12345"},
+			want:        &MessageVerdict{Reason: "validation failed: Key: 'Message.RecipientPhoneNumber' Error:Field validation for 'RecipientPhoneNumber' failed on the 'phone' tag"},
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			assert.Equal(t, tt.want, mockService.ValidateMessage(tt.req))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	EncodingGSM7 = "GSM-7"
+	EncodingUCS2 = "UCS-2"
+
+	// A single segment holds 160 GSM-7 septets or 70 UCS-2 code units. Parts of
+	// a concatenated message lose room to the user data header.
+	gsm7SingleSegment    = 160
+	gsm7MultipartSegment = 153
+	ucs2SingleSegment    = 70
+	ucs2MultipartSegment = 67
+)
+
+type SMSConfig struct {
+	MaxSegments int `mapstructure:"maxSegments"` // longest message, in segments, that is accepted
+}
+
+// gsm7Basic is the GSM 03.38 default alphabet; each character takes one septet.
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extension holds the characters sent as an escape followed by a septet
+// of the extension table, so each takes two septets.
+const gsm7Extension = "\f^{}\\[~]|€"
+
+// MeasureSegments returns the encoding an SMS with the given content is sent
+// in and the number of segments it is split into. Content that fits the GSM-7
+// alphabet is sent as GSM-7; anything else, e.g. emoji or Turkish ş and ğ,
+// forces UCS-2 for the whole message.
+func MeasureSegments(content string) (string, int) {
+	encoding := EncodingGSM7
+	units := make([]int, 0, len(content))
+	for _, r := range content {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			units = append(units, 1)
+		case strings.ContainsRune(gsm7Extension, r):
+			units = append(units, 2)
+		default:
+			encoding = EncodingUCS2
+		}
+	}
+
+	single, multipart := gsm7SingleSegment, gsm7MultipartSegment
+	if encoding == EncodingUCS2 {
+		single, multipart = ucs2SingleSegment, ucs2MultipartSegment
+
+		units = units[:0]
+		for _, r := range content {
+			if r > 0xFFFF {
+				units = append(units, 2) // a UTF-16 surrogate pair
+			} else {
+				units = append(units, 1)
+			}
+		}
+	}
+
+	return encoding, countSegments(units, single, multipart)
+}
+
+// countSegments splits characters of the given sizes into segments. A
+// character never straddles two segments, which is why an escaped GSM-7
+// character or a surrogate pair can leave a unit of a part unused.
+func countSegments(units []int, single, multipart int) int {
+	total := 0
+	for _, size := range units {
+		total += size
+	}
+
+	switch {
+	case total == 0:
+		return 0
+	case total <= single:
+		return 1
+	}
+
+	segments, used := 1, 0
+	for _, size := range units {
+		if used+size > multipart {
+			segments++
+			used = 0
+		}
+		used += size
+	}
+
+	return segments
+}
+
+// RegisterSegmentValidation registers the "segments" validation tag. A string
+// field passes when it is sent in at most maxSegments segments.
+func RegisterSegmentValidation(validate *validator.Validate, maxSegments int) error {
+	if maxSegments < 1 {
+		return fmt.Errorf("max segments must be at least 1, got %d", maxSegments)
+	}
+
+	return validate.RegisterValidation("segments", func(fl validator.FieldLevel) bool {
+		_, segments := MeasureSegments(fl.Field().String())
+		return segments <= maxSegments
+	})
+}
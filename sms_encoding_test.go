@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureSegments(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantEncoding string
+		wantSegments int
+	}{
+		{
+			name:         "should count empty content as no segment",
+			content:      "",
+			wantEncoding: EncodingGSM7,
+			wantSegments: 0,
+		},
+		{
+			name:         "should fit 160 GSM-7 characters in one segment",
+			content:      strings.Repeat("a", 160),
+			wantEncoding: EncodingGSM7,
+			wantSegments: 1,
+		},
+		{
+			name:         "should split longer GSM-7 content into 153 character parts",
+			content:      strings.Repeat("a", 307),
+			wantEncoding: EncodingGSM7,
+			wantSegments: 3,
+		},
+		{
+			name:         "should count extension characters as two septets",
+			content:      strings.Repeat("€", 80) + "a",
+			wantEncoding: EncodingGSM7,
+			wantSegments: 2,
+		},
+		{
+			name:         "should not split an escaped character across parts",
+			content:      strings.Repeat("a", 152) + "€" + strings.Repeat("a", 152),
+			wantEncoding: EncodingGSM7,
+			wantSegments: 3,
+		},
+		{
+			name:         "should send Turkish characters as UCS-2",
+			content:      "Doğrulama kodunuz: 729384",
+			wantEncoding: EncodingUCS2,
+			wantSegments: 1,
+		},
+		{
+			name:         "should split longer UCS-2 content into 67 unit parts",
+			content:      "ş" + strings.Repeat("a", 70),
+			wantEncoding: EncodingUCS2,
+			wantSegments: 2,
+		},
+		{
+			name:         "should count emoji as a surrogate pair",
+			content:      strings.Repeat("🎉", 35),
+			wantEncoding: EncodingUCS2,
+			wantSegments: 1,
+		},
+		{
+			name:         "should not split a surrogate pair across parts",
+			content:      strings.Repeat("a", 66) + "🎉" + strings.Repeat("a", 66),
+			wantEncoding: EncodingUCS2,
+			wantSegments: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding, segments := MeasureSegments(tt.content)
+			assert.Equal(t, tt.wantEncoding, encoding)
+			assert.Equal(t, tt.wantSegments, segments)
+		})
+	}
+}
+
+func TestRegisterSegmentValidation(t *testing.T) {
+	assert.Error(t, RegisterSegmentValidation(validator.New(), 0))
+
+	validate := validator.New()
+	assert.NoError(t, RegisterSegmentValidation(validate, 2))
+
+	assert.NoError(t, validate.Var(strings.Repeat("a", 306), "segments"))
+	assert.Error(t, validate.Var(strings.Repeat("a", 307), "segments"))
+	assert.NoError(t, validate.Var(strings.Repeat("ğ", 134), "segments"))
+	assert.Error(t, validate.Var(strings.Repeat("ğ", 135), "segments"))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatsService interface {
+	RetrieveStats(ctx context.Context, window time.Duration) (*MessageStats, error)
+}
+
+// MessageStats is a snapshot of queue health.
+type MessageStats struct {
+	GeneratedAt            time.Time          `json:"generated_at"`
+	Counts                 map[string]int64   `json:"counts"`                    // messages per status
+	OldestUnsentAgeSeconds int64              `json:"oldest_unsent_age_seconds"` // how long the longest waiting due message has been due
+	Throughput             SentThroughput     `json:"throughput"`
+	Latency                LatencyPercentiles `json:"latency"` // time from created_at to sent_at of messages sent within the window
+}
+
+// SentThroughput is the number of messages sent within the window and the
+// average rates it amounts to.
+type SentThroughput struct {
+	WindowSeconds int64   `json:"window_seconds"`
+	Sent          int64   `json:"sent"`
+	PerMinute     float64 `json:"per_minute"`
+	PerHour       float64 `json:"per_hour"`
+}
+
+// LatencyPercentiles holds approximate percentiles in milliseconds.
+type LatencyPercentiles struct {
+	P50 int64 `json:"p50_ms"`
+	P95 int64 `json:"p95_ms"`
+	P99 int64 `json:"p99_ms"`
+}
+
+type StatsHandler struct {
+	statsService StatsService
+}
+
+func NewStatsHandler(ss StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: ss,
+	}
+}
+
+func (h *StatsHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/stats", h.RetrieveStats)
+}
+
+// RetrieveStats godoc
+// @Summary Retrieve message statistics
+// @Description Get message counts per status, the age of the oldest due unsent message, the send rate over a window and delivery latency percentiles. Results are cached for a few seconds.
+// @Tags stats
+// @Produce json
+// @Param window query string false "Window for throughput and latency, e.g. 15m or 24h; defaults to stats.window"
+// @Success 200 {object} MessageStats
+// @Failure 400 {object} map[string]string "Invalid window"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /stats [get]
+func (h *StatsHandler) RetrieveStats(c *fiber.Ctx) error {
+	var window time.Duration
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid window, expected a duration such as 15m",
+			})
+		}
+		window = parsed
+	}
+
+	stats, err := h.statsService.RetrieveStats(c.UserContext(), window)
+	if err != nil {
+		if errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(stats)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestStatsHandler_RetrieveStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockStatsService(ctrl)
+	handler := NewStatsHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	generatedAt := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		url         string
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:       "should return stats with status 200",
+			url:        "/stats?window=15m",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"generated_at":"2025-05-10T09:00:00Z","counts":{"sent":30},"oldest_unsent_age_seconds":12,"throughput":{"window_seconds":900,"sent":30,"per_minute":2,"per_hour":120},"latency":{"p50_ms":500,"p95_ms":900,"p99_ms":1200}}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveStats(gomock.Any(), 15*time.Minute).Return(&MessageStats{
+					GeneratedAt:            generatedAt,
+					Counts:                 map[string]int64{StatusSent: 30},
+					OldestUnsentAgeSeconds: 12,
+					Throughput:             SentThroughput{WindowSeconds: 900, Sent: 30, PerMinute: 2, PerHour: 120},
+					Latency:                LatencyPercentiles{P50: 500, P95: 900, P99: 1200},
+				}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for unparsable window",
+			url:         "/stats?window=hour",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"invalid window, expected a duration such as 15m"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:       "should return error with status 400 for window out of range",
+			url:        "/stats?window=1s",
+			wantStatus: fiber.StatusBadRequest,
+			wantBody:   `{"error":"validation failed"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveStats(gomock.Any(), time.Second).Return(nil, ErrValidationFailed)
+			},
+		},
+		{
+			name:       "should return error with status 500 when service fails",
+			url:        "/stats",
+			wantStatus: fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveStats(gomock.Any(), time.Duration(0)).Return(nil, ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
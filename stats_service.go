@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	minStatsWindow = time.Minute
+	maxStatsWindow = 7 * 24 * time.Hour
+)
+
+type StatsConfig struct {
+	Window   time.Duration `mapstructure:"window"`   // default window for throughput and latency
+	CacheTTL time.Duration `mapstructure:"cacheTTL"` // how long a computed snapshot is served from Redis
+}
+
+type StatsMessageStore interface {
+	CountMessagesByStatus(ctx context.Context) (map[string]int64, error)
+	RetrieveOldestDueTime(ctx context.Context, now time.Time) (time.Time, error)
+	SentMessageStats(ctx context.Context, since time.Time) (int64, LatencyPercentiles, error)
+}
+
+type StatsCache interface {
+	SetWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
+}
+
+func statsCacheKey(window time.Duration) string {
+	return "stats:" + window.String()
+}
+
+type StatsServiceImpl struct {
+	messageStore StatsMessageStore
+	cache        StatsCache
+	config       Config
+}
+
+func NewStatsServiceImpl(ms StatsMessageStore, cache StatsCache, cfg Config) *StatsServiceImpl {
+	return &StatsServiceImpl{
+		messageStore: ms,
+		cache:        cache,
+		config:       cfg,
+	}
+}
+
+// RetrieveStats returns the message statistics for the given window, or for
+// the configured window when it is zero. A snapshot is cached for
+// stats.cacheTTL so frequent polling does not run the aggregations every time.
+// The cache only spares Mongo: when Redis fails the stats are computed anyway.
+func (ss *StatsServiceImpl) RetrieveStats(ctx context.Context, window time.Duration) (*MessageStats, error) {
+	if window == 0 {
+		window = ss.config.Stats.Window
+	}
+	if window < minStatsWindow || window > maxStatsWindow {
+		return nil, fmt.Errorf("%w: window must be between %s and %s", ErrValidationFailed, minStatsWindow, maxStatsWindow)
+	}
+
+	key := statsCacheKey(window)
+	if cached, err := ss.cache.Get(ctx, key); err == nil && cached != "" {
+		var stats MessageStats
+		if err := json.Unmarshal([]byte(cached), &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	stats, err := ss.computeStats(ctx, window, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err := json.Marshal(stats); err == nil {
+		_ = ss.cache.SetWithTTL(ctx, key, string(raw), ss.config.Stats.CacheTTL)
+	}
+
+	return stats, nil
+}
+
+func (ss *StatsServiceImpl) computeStats(ctx context.Context, window time.Duration, now time.Time) (*MessageStats, error) {
+	counts, err := ss.messageStore.CountMessagesByStatus(ctx)
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	for _, status := range []string{StatusUnsent, StatusProcessing, StatusSent, StatusFailed} {
+		if _, ok := counts[status]; !ok {
+			counts[status] = 0
+		}
+	}
+
+	stats := &MessageStats{
+		GeneratedAt: now,
+		Counts:      counts,
+		Throughput:  SentThroughput{WindowSeconds: int64(window.Seconds())},
+	}
+
+	dueAt, err := ss.messageStore.RetrieveOldestDueTime(ctx, now)
+	switch {
+	case err == nil:
+		stats.OldestUnsentAgeSeconds = int64(now.Sub(dueAt).Seconds())
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return nil, ErrInternalServerError
+	}
+
+	sent, latency, err := ss.messageStore.SentMessageStats(ctx, now.Add(-window))
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	stats.Throughput.Sent = sent
+	stats.Throughput.PerMinute = float64(sent) / window.Minutes()
+	stats.Throughput.PerHour = float64(sent) / window.Hours()
+	stats.Latency = latency
+
+	return stats, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestStatsService_RetrieveStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockStatsMessageStore(ctrl)
+	mockCache := NewMockStatsCache(ctrl)
+	service := NewStatsServiceImpl(mockStore, mockCache, Config{Stats: StatsConfig{Window: time.Hour, CacheTTL: 5 * time.Second}})
+
+	cachedStats := MessageStats{
+		GeneratedAt: time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC),
+		Counts:      map[string]int64{StatusSent: 7},
+	}
+	cachedJSON, _ := json.Marshal(cachedStats)
+
+	tests := []struct {
+		name        string
+		window      time.Duration
+		want        *MessageStats
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should return cached stats",
+			window:  0,
+			want:    &cachedStats,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), statsCacheKey(time.Hour)).Return(string(cachedJSON), nil)
+			},
+		},
+		{
+			name:   "should compute and cache stats on a cache miss",
+			window: 30 * time.Minute,
+			want: &MessageStats{
+				Counts:                 map[string]int64{StatusUnsent: 3, StatusProcessing: 0, StatusSent: 90, StatusFailed: 0, StatusExpired: 1},
+				OldestUnsentAgeSeconds: 120,
+				Throughput:             SentThroughput{WindowSeconds: 1800, Sent: 90, PerMinute: 3, PerHour: 180},
+				Latency:                LatencyPercentiles{P50: 800, P95: 2500, P99: 4000},
+			},
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), statsCacheKey(30*time.Minute)).Return("", nil)
+				mockStore.EXPECT().CountMessagesByStatus(gomock.Any()).Return(map[string]int64{StatusUnsent: 3, StatusSent: 90, StatusExpired: 1}, nil)
+				mockStore.EXPECT().RetrieveOldestDueTime(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, now time.Time) (time.Time, error) {
+					return now.Add(-2 * time.Minute), nil
+				})
+				mockStore.EXPECT().SentMessageStats(gomock.Any(), gomock.Any()).Return(int64(90), LatencyPercentiles{P50: 800, P95: 2500, P99: 4000}, nil)
+				mockCache.EXPECT().SetWithTTL(gomock.Any(), statsCacheKey(30*time.Minute), gomock.Any(), 5*time.Second).Return(nil)
+			},
+		},
+		{
+			name:   "should compute stats when cache is unavailable and queue is empty",
+			window: 0,
+			want: &MessageStats{
+				Counts:     map[string]int64{StatusUnsent: 0, StatusProcessing: 0, StatusSent: 0, StatusFailed: 0},
+				Throughput: SentThroughput{WindowSeconds: 3600},
+			},
+			wantErr: nil,
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), statsCacheKey(time.Hour)).Return("", assert.AnError)
+				mockStore.EXPECT().CountMessagesByStatus(gomock.Any()).Return(map[string]int64{}, nil)
+				mockStore.EXPECT().RetrieveOldestDueTime(gomock.Any(), gomock.Any()).Return(time.Time{}, mongo.ErrNoDocuments)
+				mockStore.EXPECT().SentMessageStats(gomock.Any(), gomock.Any()).Return(int64(0), LatencyPercentiles{}, nil)
+				mockCache.EXPECT().SetWithTTL(gomock.Any(), statsCacheKey(time.Hour), gomock.Any(), 5*time.Second).Return(assert.AnError)
+			},
+		},
+		{
+			name:        "should return validation error when window is too short",
+			window:      time.Second,
+			want:        nil,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should return internal error when aggregation fails",
+			window:  0,
+			want:    nil,
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), statsCacheKey(time.Hour)).Return("", nil)
+				mockStore.EXPECT().CountMessagesByStatus(gomock.Any()).Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.RetrieveStats(context.Background(), tt.window)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if got != nil && tt.want != nil && tt.want.GeneratedAt.IsZero() {
+				assert.False(t, got.GeneratedAt.IsZero())
+				got.GeneratedAt = time.Time{}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
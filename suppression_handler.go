@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SuppressionService interface {
+	AddSuppression(ctx context.Context, req SuppressionRequest) (*Suppression, error)
+	RemoveSuppression(ctx context.Context, phoneNumber string) error
+	RetrieveSuppression(ctx context.Context, phoneNumber string) (*Suppression, error)
+	RetrieveSuppressions(ctx context.Context, page, limit int) (*SuppressionsResponse, error)
+	ImportSuppressions(ctx context.Context, format string, body io.Reader) (*BulkImportResponse, error)
+}
+
+// Suppression is a phone number that must not receive messages, e.g. because
+// the recipient replied STOP. The phone number is the document ID.
+type Suppression struct {
+	PhoneNumber string    `bson:"_id" json:"phone_number" validate:"required,phone"`
+	Reason      string    `bson:"reason" json:"reason" validate:"max=200"`
+	Source      string    `bson:"source" json:"source"` // "api" or "import"
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+}
+
+type SuppressionRequest struct {
+	PhoneNumber string `json:"phone_number"`
+	Reason      string `json:"reason,omitempty"` // defaults to "opted out"
+}
+
+type SuppressionsResponse struct {
+	Suppressions []Suppression `json:"suppressions"`
+	Page         int           `json:"page"`
+	Limit        int           `json:"limit"`
+	Total        int64         `json:"total"`
+}
+
+type SuppressionHandler struct {
+	suppressionService SuppressionService
+}
+
+func NewSuppressionHandler(ss SuppressionService) *SuppressionHandler {
+	return &SuppressionHandler{
+		suppressionService: ss,
+	}
+}
+
+func (h *SuppressionHandler) RegisterRoutes(app *fiber.App) {
+	suppressionGroup := app.Group("/suppressions")
+	suppressionGroup.Post("/", h.AddSuppression)
+	suppressionGroup.Get("/", h.RetrieveSuppressions)
+	suppressionGroup.Post("/import", h.ImportSuppressions)
+	suppressionGroup.Get("/:phone", h.RetrieveSuppression)
+	suppressionGroup.Delete("/:phone", h.RemoveSuppression)
+}
+
+// AddSuppression godoc
+// @Summary Suppress a phone number
+// @Description Add a phone number to the suppression list. Messages to it are not sent and end as suppressed.
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param suppression body SuppressionRequest true "Phone number to suppress"
+// @Success 201 {object} Suppression
+// @Failure 400 {object} map[string]string "Invalid request body or validation error"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /suppressions [post]
+func (h *SuppressionHandler) AddSuppression(c *fiber.Ctx) error {
+	var req SuppressionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	suppression, err := h.suppressionService.AddSuppression(c.UserContext(), req)
+	if err != nil {
+		if errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(suppression)
+}
+
+// RetrieveSuppressions godoc
+// @Summary Retrieve suppressed phone numbers
+// @Description Get the suppression list, newest first
+// @Tags suppressions
+// @Produce json
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param limit query int false "Page size, at most 100" default(20)
+// @Success 200 {object} SuppressionsResponse
+// @Failure 400 {object} map[string]string "Invalid pagination parameters"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /suppressions [get]
+func (h *SuppressionHandler) RetrieveSuppressions(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", defaultPageLimit)
+	if page < 1 || limit < 1 || limit > maxPageLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid pagination parameters",
+		})
+	}
+
+	suppressions, err := h.suppressionService.RetrieveSuppressions(c.UserContext(), page, limit)
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(suppressions)
+}
+
+// RetrieveSuppression godoc
+// @Summary Retrieve a suppressed phone number
+// @Tags suppressions
+// @Produce json
+// @Param phone path string true "Phone number, URL encoded; normalized like message recipients"
+// @Success 200 {object} Suppression
+// @Failure 400 {object} map[string]string "Invalid phone number"
+// @Failure 404 {object} nil "Phone number is not suppressed"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /suppressions/{phone} [get]
+func (h *SuppressionHandler) RetrieveSuppression(c *fiber.Ctx) error {
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid phone number",
+		})
+	}
+
+	suppression, err := h.suppressionService.RetrieveSuppression(c.UserContext(), phoneNumber)
+	if err != nil {
+		if errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(suppression)
+}
+
+// RemoveSuppression godoc
+// @Summary Remove a phone number from the suppression list
+// @Description Messages to the phone number are sent again. Messages already suppressed are not requeued.
+// @Tags suppressions
+// @Param phone path string true "Phone number, URL encoded; normalized like message recipients"
+// @Success 204 {object} nil "Removed"
+// @Failure 400 {object} map[string]string "Invalid phone number"
+// @Failure 404 {object} nil "Phone number is not suppressed"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /suppressions/{phone} [delete]
+func (h *SuppressionHandler) RemoveSuppression(c *fiber.Ctx) error {
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid phone number",
+		})
+	}
+
+	if err := h.suppressionService.RemoveSuppression(c.UserContext(), phoneNumber); err != nil {
+		if errors.Is(err, ErrValidationFailed) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, ErrDocumentNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ImportSuppressions godoc
+// @Summary Import a suppression list
+// @Description Add phone numbers from an NDJSON upload of {"phone_number", "reason"} objects, or a CSV upload with a phone_number and an optional reason column
+// @Tags suppressions
+// @Accept application/x-ndjson
+// @Accept text/csv
+// @Produce json
+// @Param file body string true "NDJSON or CSV rows"
+// @Success 200 {object} BulkImportResponse
+// @Failure 400 {object} map[string]string "Unreadable upload, e.g. a CSV header without required columns"
+// @Failure 415 {object} map[string]string "Unsupported content type"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /suppressions/import [post]
+func (h *SuppressionHandler) ImportSuppressions(c *fiber.Ctx) error {
+	format, ok := bulkFormat(c.Get(fiber.HeaderContentType))
+	if !ok {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "Content-Type must be application/x-ndjson or text/csv",
+		})
+	}
+
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	report, err := h.suppressionService.ImportSuppressions(c.UserContext(), format, body)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBulkUpload) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSuppressionHandler_AddSuppression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockSuppressionService(ctrl)
+	handler := NewSuppressionHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	validRequest := SuppressionRequest{PhoneNumber: "+905551112233", Reason: "STOP keyword"}
+
+	tests := []struct {
+		name        string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should suppress phone number with status 201",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusCreated,
+			wantBody:    `{"phone_number":"+905551112233","reason":"STOP keyword","source":"api","created_at":"0001-01-01T00:00:00Z"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().AddSuppression(gomock.Any(), validRequest).Return(&Suppression{PhoneNumber: "+905551112233", Reason: "STOP keyword", Source: SuppressionSourceAPI}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 when validation fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"validation failed: phone_number must be E.164"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().AddSuppression(gomock.Any(), validRequest).Return(nil, fmt.Errorf("%w: phone_number must be E.164", ErrValidationFailed))
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+		{
+			name:        "should return status 500 when service fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusInternalServerError,
+			beforeSuite: func() {
+				mockService.EXPECT().AddSuppression(gomock.Any(), validRequest).Return(nil, ErrInternalServerError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, "/suppressions", reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestSuppressionHandler_SuppressionByPhone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockSuppressionService(ctrl)
+	handler := NewSuppressionHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	phoneNumber := "+905551112233"
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:       "should retrieve suppression by URL encoded phone number",
+			method:     fiber.MethodGet,
+			path:       "/suppressions/%2B905551112233",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"phone_number":"+905551112233","reason":"opted out","source":"import","created_at":"0001-01-01T00:00:00Z"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).Return(&Suppression{PhoneNumber: phoneNumber, Reason: "opted out", Source: SuppressionSourceImport}, nil)
+			},
+		},
+		{
+			name:       "should return status 404 when phone number is not suppressed",
+			method:     fiber.MethodGet,
+			path:       "/suppressions/%2B905551112233",
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).Return(nil, ErrDocumentNotFound)
+			},
+		},
+		{
+			name:       "should remove suppression with status 204",
+			method:     fiber.MethodDelete,
+			path:       "/suppressions/%2B905551112233",
+			wantStatus: fiber.StatusNoContent,
+			beforeSuite: func() {
+				mockService.EXPECT().RemoveSuppression(gomock.Any(), phoneNumber).Return(nil)
+			},
+		},
+		{
+			name:       "should return status 404 when removing a number that is not suppressed",
+			method:     fiber.MethodDelete,
+			path:       "/suppressions/%2B905551112233",
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RemoveSuppression(gomock.Any(), phoneNumber).Return(ErrDocumentNotFound)
+			},
+		},
+		{
+			name:       "should list suppressions",
+			method:     fiber.MethodGet,
+			path:       "/suppressions?page=2&limit=10",
+			wantStatus: fiber.StatusOK,
+			wantBody:   `{"suppressions":[],"page":2,"limit":10,"total":10}`,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveSuppressions(gomock.Any(), 2, 10).Return(&SuppressionsResponse{Suppressions: []Suppression{}, Page: 2, Limit: 10, Total: 10}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid pagination",
+			method:      fiber.MethodGet,
+			path:        "/suppressions?page=0",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid pagination parameters"}`,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestSuppressionHandler_ImportSuppressions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockSuppressionService(ctrl)
+	handler := NewSuppressionHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	tests := []struct {
+		name        string
+		contentType string
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should return import report",
+			contentType: "text/csv",
+			wantStatus:  fiber.StatusOK,
+			wantBody:    `{"total":1,"inserted":1,"errors":[]}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ImportSuppressions(gomock.Any(), BulkFormatCSV, gomock.Any()).Return(&BulkImportResponse{Total: 1, Inserted: 1, Errors: []BulkRowError{}}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for an unreadable upload",
+			contentType: "text/csv",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"invalid bulk upload: CSV header is missing the phone_number column"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().ImportSuppressions(gomock.Any(), BulkFormatCSV, gomock.Any()).Return(nil, fmt.Errorf("%w: CSV header is missing the phone_number column", ErrInvalidBulkUpload))
+			},
+		},
+		{
+			name:        "should return error with status 415 for unsupported content type",
+			contentType: "application/json",
+			wantStatus:  fiber.StatusUnsupportedMediaType,
+			wantBody:    `{"error":"Content-Type must be application/x-ndjson or text/csv"}`,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			req := httptest.NewRequest(fiber.MethodPost, "/suppressions/import", strings.NewReader("phone_number\n+905551112233\n"))
+			req.Header.Set("Content-Type", tt.contentType)
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SuppressionRepositoryImpl struct {
+	suppressionCollection *mongo.Collection
+}
+
+func NewSuppressionRepositoryImpl(collection *mongo.Collection) *SuppressionRepositoryImpl {
+	return &SuppressionRepositoryImpl{
+		suppressionCollection: collection,
+	}
+}
+
+// EnsureIndexes creates the index used to list suppressions, newest first.
+func (sr *SuppressionRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := sr.suppressionCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}},
+	})
+
+	return err
+}
+
+func suppressionUpsert(suppression Suppression) (bson.M, bson.M) {
+	filter := bson.M{"_id": suppression.PhoneNumber}
+	update := bson.M{
+		"$set": bson.M{
+			"reason": suppression.Reason,
+			"source": suppression.Source,
+		},
+		"$setOnInsert": bson.M{
+			"created_at": suppression.CreatedAt,
+		},
+	}
+
+	return filter, update
+}
+
+// UpsertSuppression adds the phone number to the suppression list, or updates
+// the reason of an existing entry.
+func (sr *SuppressionRepositoryImpl) UpsertSuppression(ctx context.Context, suppression *Suppression) error {
+	filter, update := suppressionUpsert(*suppression)
+	_, err := sr.suppressionCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// UpsertSuppressions upserts a batch of suppressions in one round trip.
+func (sr *SuppressionRepositoryImpl) UpsertSuppressions(ctx context.Context, suppressions []Suppression) error {
+	models := make([]mongo.WriteModel, len(suppressions))
+	for i, suppression := range suppressions {
+		filter, update := suppressionUpsert(suppression)
+		models[i] = mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true)
+	}
+
+	_, err := sr.suppressionCollection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (sr *SuppressionRepositoryImpl) RetrieveSuppression(ctx context.Context, phoneNumber string) (*Suppression, error) {
+	var suppression Suppression
+	err := sr.suppressionCollection.FindOne(ctx, bson.M{"_id": phoneNumber}).Decode(&suppression)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+
+	return &suppression, nil
+}
+
+func (sr *SuppressionRepositoryImpl) RetrieveSuppressions(ctx context.Context, page, limit int) ([]Suppression, int64, error) {
+	total, err := sr.suppressionCollection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := sr.suppressionCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var suppressions []Suppression
+	if err := cursor.All(ctx, &suppressions); err != nil {
+		return nil, 0, ErrDocumentDecodingFailed
+	}
+
+	return suppressions, total, nil
+}
+
+func (sr *SuppressionRepositoryImpl) DeleteSuppression(ctx context.Context, phoneNumber string) error {
+	result, err := sr.suppressionCollection.DeleteOne(ctx, bson.M{"_id": phoneNumber})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const testSuppressionCollection = "suppressions"
+
+func TestSuppressionRepository_CRUD(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	suppressionRepository := NewSuppressionRepositoryImpl(client.Database(testDB).Collection(testSuppressionCollection))
+	assert.NoError(t, suppressionRepository.EnsureIndexes(context.Background()))
+
+	createdAt := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	suppression := &Suppression{PhoneNumber: "+15553579024", Reason: "STOP keyword", Source: SuppressionSourceAPI, CreatedAt: createdAt}
+	assert.NoError(t, suppressionRepository.UpsertSuppression(context.Background(), suppression))
+
+	// Suppressing the number again updates the reason but keeps the original creation time.
+	assert.NoError(t, suppressionRepository.UpsertSuppression(context.Background(), &Suppression{PhoneNumber: "+15553579024", Reason: "complaint", Source: SuppressionSourceAPI, CreatedAt: createdAt.Add(time.Hour)}))
+
+	got, err := suppressionRepository.RetrieveSuppression(context.Background(), "+15553579024")
+	assert.NoError(t, err)
+	assert.Equal(t, "complaint", got.Reason)
+	assert.True(t, createdAt.Equal(got.CreatedAt))
+
+	assert.NoError(t, suppressionRepository.UpsertSuppressions(context.Background(), []Suppression{
+		{PhoneNumber: "+15553579025", Reason: "opted out", Source: SuppressionSourceImport, CreatedAt: createdAt.Add(2 * time.Hour)},
+		{PhoneNumber: "+15553579024", Reason: "opted out", Source: SuppressionSourceImport, CreatedAt: createdAt.Add(2 * time.Hour)},
+	}))
+
+	suppressions, total, err := suppressionRepository.RetrieveSuppressions(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, suppressions, 1)
+	assert.Equal(t, "+15553579025", suppressions[0].PhoneNumber)
+
+	assert.NoError(t, suppressionRepository.DeleteSuppression(context.Background(), "+15553579024"))
+	assert.Equal(t, mongo.ErrNoDocuments, suppressionRepository.DeleteSuppression(context.Background(), "+15553579024"))
+
+	_, err = suppressionRepository.RetrieveSuppression(context.Background(), "+15553579024")
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
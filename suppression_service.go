@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	SuppressionSourceAPI    = "api"
+	SuppressionSourceImport = "import"
+
+	defaultSuppressionReason = "opted out"
+
+	csvPhoneNumberColumn = "phone_number"
+	csvReasonColumn      = "reason"
+
+	// Cached suppression lookups hold either suppressionCachePrefix followed by
+	// the reason, or suppressionCacheClear for numbers that are not suppressed.
+	suppressionCachePrefix = "suppressed:"
+	suppressionCacheClear  = "clear"
+
+	// suppressionLookupTTL bounds how long a lookup cached by SuppressionReason
+	// can outlive a suppression that was added or removed while the database was
+	// being read.
+	suppressionLookupTTL = time.Minute
+)
+
+type SuppressionRepository interface {
+	UpsertSuppression(ctx context.Context, suppression *Suppression) error
+	UpsertSuppressions(ctx context.Context, suppressions []Suppression) error
+	RetrieveSuppression(ctx context.Context, phoneNumber string) (*Suppression, error)
+	RetrieveSuppressions(ctx context.Context, page, limit int) ([]Suppression, int64, error)
+	DeleteSuppression(ctx context.Context, phoneNumber string) error
+}
+
+type SuppressionCache interface {
+	Set(ctx context.Context, key string, value string) error
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	Del(ctx context.Context, key string) error
+}
+
+func suppressionCacheKey(phoneNumber string) string {
+	return "suppression:" + phoneNumber
+}
+
+type SuppressionServiceImpl struct {
+	suppressionRepository SuppressionRepository
+	cache                 SuppressionCache
+	config                Config
+	validate              *validator.Validate
+}
+
+func NewSuppressionServiceImpl(sr SuppressionRepository, cache SuppressionCache, cfg Config, validate *validator.Validate) *SuppressionServiceImpl {
+	return &SuppressionServiceImpl{
+		suppressionRepository: sr,
+		cache:                 cache,
+		config:                cfg,
+		validate:              validate,
+	}
+}
+
+func newSuppression(phoneNumber, reason, source string, now time.Time) *Suppression {
+	if reason == "" {
+		reason = defaultSuppressionReason
+	}
+
+	return &Suppression{
+		PhoneNumber: phoneNumber,
+		Reason:      reason,
+		Source:      source,
+		CreatedAt:   now,
+	}
+}
+
+func (ss *SuppressionServiceImpl) AddSuppression(ctx context.Context, req SuppressionRequest) (*Suppression, error) {
+	suppression := newSuppression(req.PhoneNumber, req.Reason, SuppressionSourceAPI, time.Now())
+	if err := ss.validate.Struct(suppression); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+	if err := normalizePhoneNumberField(&suppression.PhoneNumber, ss.config.Phone.DefaultRegion); err != nil {
+		return nil, err
+	}
+
+	if err := ss.suppressionRepository.UpsertSuppression(ctx, suppression); err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if err := ss.cache.Set(ctx, suppressionCacheKey(suppression.PhoneNumber), suppressionCachePrefix+suppression.Reason); err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	return suppression, nil
+}
+
+// RemoveSuppression deletes the phone number from the suppression list. The
+// cached lookup is dropped even if the number was not on the list, so a stale
+// entry cannot keep blocking it.
+func (ss *SuppressionServiceImpl) RemoveSuppression(ctx context.Context, phoneNumber string) error {
+	phoneNumber, err := NormalizePhoneNumber(phoneNumber, ss.config.Phone.DefaultRegion)
+	if err != nil {
+		return fmt.Errorf("%w: phone number is not valid", ErrValidationFailed)
+	}
+
+	err = ss.suppressionRepository.DeleteSuppression(ctx, phoneNumber)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrInternalServerError
+	}
+
+	if err := ss.cache.Del(ctx, suppressionCacheKey(phoneNumber)); err != nil {
+		return ErrInternalServerError
+	}
+
+	if err != nil {
+		return ErrDocumentNotFound
+	}
+
+	return nil
+}
+
+func (ss *SuppressionServiceImpl) RetrieveSuppression(ctx context.Context, phoneNumber string) (*Suppression, error) {
+	phoneNumber, err := NormalizePhoneNumber(phoneNumber, ss.config.Phone.DefaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: phone number is not valid", ErrValidationFailed)
+	}
+
+	suppression, err := ss.suppressionRepository.RetrieveSuppression(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, ErrInternalServerError
+	}
+
+	return suppression, nil
+}
+
+func (ss *SuppressionServiceImpl) RetrieveSuppressions(ctx context.Context, page, limit int) (*SuppressionsResponse, error) {
+	suppressions, total, err := ss.suppressionRepository.RetrieveSuppressions(ctx, page, limit)
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if suppressions == nil {
+		suppressions = []Suppression{}
+	}
+
+	return &SuppressionsResponse{
+		Suppressions: suppressions,
+		Page:         page,
+		Limit:        limit,
+		Total:        total,
+	}, nil
+}
+
+// ImportSuppressions adds every valid row of an upload to the suppression list
+// in batches. Invalid or failed rows are reported by line and never stop the
+// rest of the upload.
+func (ss *SuppressionServiceImpl) ImportSuppressions(ctx context.Context, format string, body io.Reader) (*BulkImportResponse, error) {
+	batchSize := max(ss.config.BulkImport.BatchSize, 1)
+	report := &BulkImportResponse{Errors: []BulkRowError{}}
+	now := time.Now()
+
+	var (
+		batch      []Suppression
+		batchLines []int
+		lastLine   int
+	)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		err := ss.suppressionRepository.UpsertSuppressions(ctx, batch)
+		for i, line := range batchLines {
+			if err != nil {
+				report.Errors = append(report.Errors, BulkRowError{Line: line, Error: "failed to store suppression"})
+				continue
+			}
+
+			// A row whose cached lookup could not be written may still be read as
+			// not suppressed until the cached entry expires, so it is reported.
+			suppression := batch[i]
+			if err := ss.cache.Set(ctx, suppressionCacheKey(suppression.PhoneNumber), suppressionCachePrefix+suppression.Reason); err != nil {
+				report.Errors = append(report.Errors, BulkRowError{Line: line, Error: "suppression stored but its cached lookup could not be updated"})
+				continue
+			}
+
+			report.Inserted++
+		}
+
+		batch, batchLines = batch[:0], batchLines[:0]
+	}
+
+	readErr := readSuppressionRows(format, body, func(line int, row SuppressionRequest, rowErr error) {
+		report.Total++
+		lastLine = line
+
+		if rowErr != nil {
+			report.Errors = append(report.Errors, BulkRowError{Line: line, Error: rowErr.Error()})
+			return
+		}
+
+		suppression := newSuppression(row.PhoneNumber, row.Reason, SuppressionSourceImport, now)
+		if err := ss.validate.Struct(suppression); err != nil {
+			report.Errors = append(report.Errors, BulkRowError{Line: line, Error: fmt.Sprintf("%s: %s", ErrValidationFailed, err.Error())})
+			return
+		}
+		if err := normalizePhoneNumberField(&suppression.PhoneNumber, ss.config.Phone.DefaultRegion); err != nil {
+			report.Errors = append(report.Errors, BulkRowError{Line: line, Error: err.Error()})
+			return
+		}
+
+		batch = append(batch, *suppression)
+		batchLines = append(batchLines, line)
+		if len(batch) >= batchSize {
+			flush()
+		}
+	})
+
+	if errors.Is(readErr, ErrUnsupportedBulkFormat) || (errors.Is(readErr, ErrInvalidBulkUpload) && report.Total == 0) {
+		return nil, readErr
+	}
+
+	flush()
+
+	if readErr != nil {
+		report.Errors = append(report.Errors, BulkRowError{Line: lastLine + 1, Error: "upload aborted: " + readErr.Error()})
+	}
+
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+
+	return report, nil
+}
+
+// SuppressionReason returns why the phone number is suppressed, or an empty
+// string when it is not. Lookups are cached in both directions; a cache
+// failure falls back to the database. Lookups are only cached with SetNX and a
+// short TTL, so they never overwrite the entry written by AddSuppression, and a
+// reason read just before RemoveSuppression cannot block the number for longer
+// than suppressionLookupTTL.
+func (ss *SuppressionServiceImpl) SuppressionReason(ctx context.Context, phoneNumber string) (string, error) {
+	key := suppressionCacheKey(phoneNumber)
+
+	if cached, err := ss.cache.Get(ctx, key); err == nil && cached != "" {
+		if reason, ok := strings.CutPrefix(cached, suppressionCachePrefix); ok {
+			return reason, nil
+		}
+		if cached == suppressionCacheClear {
+			return "", nil
+		}
+	}
+
+	suppression, err := ss.suppressionRepository.RetrieveSuppression(ctx, phoneNumber)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			_, _ = ss.cache.SetNX(ctx, key, suppressionCacheClear, suppressionLookupTTL)
+			return "", nil
+		}
+		return "", err
+	}
+
+	_, _ = ss.cache.SetNX(ctx, key, suppressionCachePrefix+suppression.Reason, suppressionLookupTTL)
+	return suppression.Reason, nil
+}
+
+// readSuppressionRows streams an upload of phone numbers. NDJSON rows are
+// {"phone_number": ..., "reason": ...} objects; CSV uploads need a header with
+// a phone_number column and may add a reason column.
+func readSuppressionRows(format string, r io.Reader, fn bulkRowFunc[SuppressionRequest]) error {
+	return readRows(format, r, []string{csvPhoneNumberColumn}, csvRecordToSuppression, fn)
+}
+
+func csvRecordToSuppression(columns map[string]int, record []string) (SuppressionRequest, error) {
+	row := SuppressionRequest{PhoneNumber: record[columns[csvPhoneNumberColumn]]}
+	if i, ok := columns[csvReasonColumn]; ok {
+		row.Reason = record[i]
+	}
+
+	return row, nil
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSuppressionService_AddSuppression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockSuppressionRepository(ctrl)
+	mockCache := NewMockSuppressionCache(ctrl)
+	service := NewSuppressionServiceImpl(mockRepo, mockCache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	tests := []struct {
+		name        string
+		req         SuppressionRequest
+		wantReason  string
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:       "should store and cache the suppression",
+			req:        SuppressionRequest{PhoneNumber: "+905551112233", Reason: "STOP keyword"},
+			wantReason: "STOP keyword",
+			wantErr:    nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppression(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, suppression *Suppression) error {
+					assert.Equal(t, "+905551112233", suppression.PhoneNumber)
+					assert.Equal(t, SuppressionSourceAPI, suppression.Source)
+					return nil
+				})
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112233"), "suppressed:STOP keyword").Return(nil)
+			},
+		},
+		{
+			name:       "should default the reason",
+			req:        SuppressionRequest{PhoneNumber: "+905551112233"},
+			wantReason: defaultSuppressionReason,
+			wantErr:    nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppression(gomock.Any(), gomock.Any()).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112233"), "suppressed:opted out").Return(nil)
+			},
+		},
+		{
+			name:       "should store the normalized phone number",
+			req:        SuppressionRequest{PhoneNumber: "0555 111 22 33"},
+			wantReason: defaultSuppressionReason,
+			wantErr:    nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppression(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, suppression *Suppression) error {
+					assert.Equal(t, "+905551112233", suppression.PhoneNumber)
+					return nil
+				})
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112233"), "suppressed:opted out").Return(nil)
+			},
+		},
+		{
+			name:        "should return validation error for an impossible phone number",
+			req:         SuppressionRequest{PhoneNumber: "+90555"},
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should return internal error when repository fails",
+			req:     SuppressionRequest{PhoneNumber: "+905551112233"},
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppression(gomock.Any(), gomock.Any()).Return(assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.AddSuppression(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.wantReason, got.Reason)
+			}
+		})
+	}
+}
+
+func TestSuppressionService_RemoveSuppression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockSuppressionRepository(ctrl)
+	mockCache := NewMockSuppressionCache(ctrl)
+	service := NewSuppressionServiceImpl(mockRepo, mockCache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	phoneNumber := "+905551112233"
+
+	tests := []struct {
+		name        string
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should delete the suppression and its cached lookup",
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().DeleteSuppression(gomock.Any(), phoneNumber).Return(nil)
+				mockCache.EXPECT().Del(gomock.Any(), suppressionCacheKey(phoneNumber)).Return(nil)
+			},
+		},
+		{
+			name:    "should drop the cached lookup and return not found when number is not suppressed",
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().DeleteSuppression(gomock.Any(), phoneNumber).Return(mongo.ErrNoDocuments)
+				mockCache.EXPECT().Del(gomock.Any(), suppressionCacheKey(phoneNumber)).Return(nil)
+			},
+		},
+		{
+			name:    "should return internal error when repository fails",
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().DeleteSuppression(gomock.Any(), phoneNumber).Return(assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			err := service.RemoveSuppression(context.Background(), phoneNumber)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSuppressionService_SuppressionReason(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockSuppressionRepository(ctrl)
+	mockCache := NewMockSuppressionCache(ctrl)
+	service := NewSuppressionServiceImpl(mockRepo, mockCache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	phoneNumber := "+905551112233"
+	key := suppressionCacheKey(phoneNumber)
+
+	tests := []struct {
+		name        string
+		want        string
+		wantErr     bool
+		beforeSuite func()
+	}{
+		{
+			name: "should return cached reason",
+			want: "STOP keyword",
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), key).Return("suppressed:STOP keyword", nil)
+			},
+		},
+		{
+			name: "should return empty reason for a cached clear lookup",
+			want: "",
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(suppressionCacheClear, nil)
+			},
+		},
+		{
+			name: "should load and cache the reason on a cache miss",
+			want: "complaint",
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), key).Return("", nil)
+				mockRepo.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).Return(&Suppression{PhoneNumber: phoneNumber, Reason: "complaint"}, nil)
+				mockCache.EXPECT().SetNX(gomock.Any(), key, "suppressed:complaint", suppressionLookupTTL).Return(true, nil)
+			},
+		},
+		{
+			name: "should cache numbers that are not suppressed",
+			want: "",
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), key).Return("", nil)
+				mockRepo.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).Return(nil, mongo.ErrNoDocuments)
+				mockCache.EXPECT().SetNX(gomock.Any(), key, suppressionCacheClear, suppressionLookupTTL).Return(true, nil)
+			},
+		},
+		{
+			name: "should fall back to the database when the cache fails",
+			want: "complaint",
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), key).Return("", assert.AnError)
+				mockRepo.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).Return(&Suppression{PhoneNumber: phoneNumber, Reason: "complaint"}, nil)
+				mockCache.EXPECT().SetNX(gomock.Any(), key, "suppressed:complaint", suppressionLookupTTL).Return(false, assert.AnError)
+			},
+		},
+		{
+			name:    "should return repository errors",
+			wantErr: true,
+			beforeSuite: func() {
+				mockCache.EXPECT().Get(gomock.Any(), key).Return("", nil)
+				mockRepo.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.SuppressionReason(context.Background(), phoneNumber)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSuppressionService_ImportSuppressions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockSuppressionRepository(ctrl)
+	mockCache := NewMockSuppressionCache(ctrl)
+	service := NewSuppressionServiceImpl(mockRepo, mockCache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}, BulkImport: BulkImportConfig{BatchSize: 2}}, newTestValidator())
+
+	tests := []struct {
+		name        string
+		format      string
+		body        string
+		want        *BulkImportResponse
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:   "should import valid CSV rows in batches and report invalid ones",
+			format: BulkFormatCSV,
+			body:   "phone_number,reason\n+905551112233,STOP keyword\n12345,bad\n+905551112234,\n+905551112235,complaint\n",
+			want: &BulkImportResponse{
+				Total:    4,
+				Inserted: 3,
+				Errors:   []BulkRowError{{Line: 3}},
+			},
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(2)).DoAndReturn(func(_ context.Context, suppressions []Suppression) error {
+					assert.Equal(t, SuppressionSourceImport, suppressions[0].Source)
+					assert.Equal(t, defaultSuppressionReason, suppressions[1].Reason)
+					return nil
+				})
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(1)).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(3)
+			},
+		},
+		{
+			name:   "should import NDJSON rows",
+			format: BulkFormatNDJSON,
+			body:   "{\"phone_number\":\"+905551112233\",\"reason\":\"STOP keyword\"}\nnot json\n",
+			want: &BulkImportResponse{
+				Total:    2,
+				Inserted: 1,
+				Errors:   []BulkRowError{{Line: 2}},
+			},
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(1)).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112233"), "suppressed:STOP keyword").Return(nil)
+			},
+		},
+		{
+			name:        "should reject a CSV header without a phone number column",
+			format:      BulkFormatCSV,
+			body:        "number,reason\n+905551112233,STOP\n",
+			wantErr:     ErrInvalidBulkUpload,
+			beforeSuite: func() {},
+		},
+		{
+			name:   "should report the rows of a failed batch and keep importing",
+			format: BulkFormatNDJSON,
+			body:   "{\"phone_number\":\"+905551112233\"}\n{\"phone_number\":\"+905551112234\"}\n{\"phone_number\":\"+905551112235\"}\n",
+			want: &BulkImportResponse{
+				Total:    3,
+				Inserted: 1,
+				Errors:   []BulkRowError{{Line: 1}, {Line: 2}},
+			},
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(2)).Return(assert.AnError)
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(1)).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112235"), "suppressed:opted out").Return(nil)
+			},
+		},
+		{
+			name:   "should report rows whose cached lookup could not be written",
+			format: BulkFormatNDJSON,
+			body:   "{\"phone_number\":\"+905551112233\"}\n{\"phone_number\":\"+905551112234\"}\n",
+			want: &BulkImportResponse{
+				Total:    2,
+				Inserted: 1,
+				Errors:   []BulkRowError{{Line: 2}},
+			},
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(2)).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112233"), gomock.Any()).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112234"), gomock.Any()).Return(assert.AnError)
+			},
+		},
+		{
+			name:   "should report the line after the last one read when the upload breaks off",
+			format: BulkFormatNDJSON,
+			body:   "{\"phone_number\":\"+905551112233\"}\n" + strings.Repeat("x", maxBulkLineSize+1) + "\n",
+			want: &BulkImportResponse{
+				Total:    1,
+				Inserted: 1,
+				Errors:   []BulkRowError{{Line: 2}},
+			},
+			beforeSuite: func() {
+				mockRepo.EXPECT().UpsertSuppressions(gomock.Any(), gomock.Len(1)).Return(nil)
+				mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey("+905551112233"), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.ImportSuppressions(context.Background(), tt.format, strings.NewReader(tt.body))
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+
+			assert.Equal(t, tt.want.Total, got.Total)
+			assert.Equal(t, tt.want.Inserted, got.Inserted)
+			assert.Len(t, got.Errors, len(tt.want.Errors))
+			for i, rowErr := range tt.want.Errors {
+				assert.Equal(t, rowErr.Line, got.Errors[i].Line)
+			}
+		})
+	}
+}
+
+// memorySuppressionCache is a SuppressionCache with Redis' SET and SETNX
+// semantics, used to check how concurrent cache writes interleave. Entries
+// never expire; their TTLs are only recorded.
+type memorySuppressionCache struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newMemorySuppressionCache() *memorySuppressionCache {
+	return &memorySuppressionCache{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (c *memorySuppressionCache) Set(_ context.Context, key string, value string) error {
+	c.values[key] = value
+	delete(c.ttls, key)
+	return nil
+}
+
+func (c *memorySuppressionCache) SetNX(_ context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	if _, ok := c.values[key]; ok {
+		return false, nil
+	}
+	c.values[key] = value
+	c.ttls[key] = ttl
+	return true, nil
+}
+
+func (c *memorySuppressionCache) Get(_ context.Context, key string) (string, error) {
+	return c.values[key], nil
+}
+
+func (c *memorySuppressionCache) Del(_ context.Context, key string) error {
+	delete(c.values, key)
+	delete(c.ttls, key)
+	return nil
+}
+
+func TestSuppressionService_SuppressionReason_ConcurrentAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockSuppressionRepository(ctrl)
+	cache := newMemorySuppressionCache()
+	service := NewSuppressionServiceImpl(mockRepo, cache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	phoneNumber := "+905551112233"
+
+	// The number is suppressed after the lookup read the database but before it
+	// cached the result, so the lookup must not overwrite the suppression.
+	mockRepo.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).DoAndReturn(func(ctx context.Context, _ string) (*Suppression, error) {
+		_, err := service.AddSuppression(ctx, SuppressionRequest{PhoneNumber: phoneNumber, Reason: "STOP keyword"})
+		assert.NoError(t, err)
+		return nil, mongo.ErrNoDocuments
+	})
+	mockRepo.EXPECT().UpsertSuppression(gomock.Any(), gomock.Any()).Return(nil)
+
+	got, err := service.SuppressionReason(context.Background(), phoneNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, "", got)
+
+	got, err = service.SuppressionReason(context.Background(), phoneNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, "STOP keyword", got)
+}
+
+func TestSuppressionService_SuppressionReason_ConcurrentRemove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockSuppressionRepository(ctrl)
+	cache := newMemorySuppressionCache()
+	service := NewSuppressionServiceImpl(mockRepo, cache, Config{Phone: PhoneConfig{DefaultRegion: testPhoneRegion}}, newTestValidator())
+
+	phoneNumber := "+905551112233"
+	key := suppressionCacheKey(phoneNumber)
+
+	// The suppression is removed after the lookup read it but before it cached
+	// the result, so the cached reason must expire on its own.
+	mockRepo.EXPECT().RetrieveSuppression(gomock.Any(), phoneNumber).DoAndReturn(func(ctx context.Context, _ string) (*Suppression, error) {
+		assert.NoError(t, service.RemoveSuppression(ctx, phoneNumber))
+		return &Suppression{PhoneNumber: phoneNumber, Reason: "STOP keyword"}, nil
+	})
+	mockRepo.EXPECT().DeleteSuppression(gomock.Any(), phoneNumber).Return(nil)
+
+	got, err := service.SuppressionReason(context.Background(), phoneNumber)
+	assert.NoError(t, err)
+	assert.Equal(t, "STOP keyword", got)
+	assert.Equal(t, suppressionLookupTTL, cache.ttls[key])
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, req TemplateRequest) (primitive.ObjectID, error)
+	RetrieveTemplates(ctx context.Context) ([]MessageTemplate, error)
+	RetrieveTemplate(ctx context.Context, templateID primitive.ObjectID) (*MessageTemplate, error)
+	UpdateTemplate(ctx context.Context, templateID primitive.ObjectID, req TemplateRequest) (*MessageTemplate, error)
+	DeleteTemplate(ctx context.Context, templateID primitive.ObjectID) error
+}
+
+// MessageTemplate is reusable message content with named placeholders such as
+// "Your verification code is: {{code}}". Variables lists the placeholder names.
+type MessageTemplate struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name      string             `bson:"name" json:"name" validate:"required,max=100"`
+	Content   string             `bson:"content" json:"content" validate:"required,max=1000"`
+	Variables []string           `bson:"variables" json:"variables"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+type TemplateRequest struct {
+	Name    string `json:"name"`
+	Content string `json:"content"` // placeholders are written as {{name}}
+}
+
+type CreateTemplateResponse struct {
+	ID string `json:"id"`
+}
+
+type TemplateHandler struct {
+	templateService TemplateService
+}
+
+func NewTemplateHandler(ts TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: ts,
+	}
+}
+
+func (h *TemplateHandler) RegisterRoutes(app *fiber.App) {
+	templateGroup := app.Group("/templates")
+	templateGroup.Post("/", h.CreateTemplate)
+	templateGroup.Get("/", h.RetrieveTemplates)
+	templateGroup.Get("/:id", h.RetrieveTemplate)
+	templateGroup.Put("/:id", h.UpdateTemplate)
+	templateGroup.Delete("/:id", h.DeleteTemplate)
+}
+
+// CreateTemplate godoc
+// @Summary Create a message template
+// @Description Create reusable message content with {{name}} placeholders
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body TemplateRequest true "Template to create"
+// @Success 201 {object} CreateTemplateResponse
+// @Failure 400 {object} map[string]string "Invalid request body or validation error"
+// @Failure 409 {object} map[string]string "Template name is already taken"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *fiber.Ctx) error {
+	var req TemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	id, err := h.templateService.CreateTemplate(c.UserContext(), req)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(CreateTemplateResponse{
+		ID: id.Hex(),
+	})
+}
+
+// RetrieveTemplates godoc
+// @Summary Retrieve message templates
+// @Description Get all message templates ordered by name
+// @Tags templates
+// @Produce json
+// @Success 200 {array} MessageTemplate
+// @Failure 500 {object} nil "Internal server error"
+// @Router /templates [get]
+func (h *TemplateHandler) RetrieveTemplates(c *fiber.Ctx) error {
+	templates, err := h.templateService.RetrieveTemplates(c.UserContext())
+	if err != nil {
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return c.JSON(templates)
+}
+
+// RetrieveTemplate godoc
+// @Summary Retrieve a message template
+// @Tags templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} MessageTemplate
+// @Failure 400 {object} map[string]string "Invalid template ID"
+// @Failure 404 {object} nil "Template not found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /templates/{id} [get]
+func (h *TemplateHandler) RetrieveTemplate(c *fiber.Ctx) error {
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidTemplateID.Error(),
+		})
+	}
+
+	template, err := h.templateService.RetrieveTemplate(c.UserContext(), templateID)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update a message template
+// @Description Replace the name and content of a template. Messages not dispatched yet are rendered with the new content.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param template body TemplateRequest true "New template name and content"
+// @Success 200 {object} MessageTemplate
+// @Failure 400 {object} map[string]string "Invalid template ID, request body or validation error"
+// @Failure 404 {object} nil "Template not found"
+// @Failure 409 {object} map[string]string "Template name is already taken"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidTemplateID.Error(),
+		})
+	}
+
+	var req TemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	template, err := h.templateService.UpdateTemplate(c.UserContext(), templateID, req)
+	if err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.JSON(template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a message template
+// @Description Delete a template. Messages still referencing it fail as invalid_content when dispatched.
+// @Tags templates
+// @Param id path string true "Template ID"
+// @Success 204 {object} nil "Deleted"
+// @Failure 400 {object} map[string]string "Invalid template ID"
+// @Failure 404 {object} nil "Template not found"
+// @Failure 500 {object} nil "Internal server error"
+// @Router /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *fiber.Ctx) error {
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": ErrInvalidTemplateID.Error(),
+		})
+	}
+
+	if err := h.templateService.DeleteTemplate(c.UserContext(), templateID); err != nil {
+		return h.errorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *TemplateHandler) errorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrValidationFailed):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, ErrDocumentNotFound):
+		return c.SendStatus(fiber.StatusNotFound)
+	case errors.Is(err, ErrTemplateNameTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockTemplateService(ctrl)
+	handler := NewTemplateHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	createdID := primitive.NewObjectID()
+	validRequest := TemplateRequest{Name: "otp", Content: "Your verification code is: {{code}}"}
+
+	tests := []struct {
+		name        string
+		requestBody interface{}
+		wantStatus  int
+		wantBody    string
+		beforeSuite func()
+	}{
+		{
+			name:        "should create template with status 201",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusCreated,
+			wantBody:    fmt.Sprintf(`{"id":"%s"}`, createdID.Hex()),
+			beforeSuite: func() {
+				mockService.EXPECT().CreateTemplate(gomock.Any(), validRequest).Return(createdID, nil)
+			},
+		},
+		{
+			name:        "should return error with status 409 when name is taken",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusConflict,
+			wantBody:    `{"error":"template name is already taken"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateTemplate(gomock.Any(), validRequest).Return(primitive.NilObjectID, ErrTemplateNameTaken)
+			},
+		},
+		{
+			name:        "should return error with status 400 when validation fails",
+			requestBody: validRequest,
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"validation failed: name is required"}`,
+			beforeSuite: func() {
+				mockService.EXPECT().CreateTemplate(gomock.Any(), validRequest).Return(primitive.NilObjectID, fmt.Errorf("%w: name is required", ErrValidationFailed))
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid request body",
+			requestBody: "invalid json",
+			wantStatus:  fiber.StatusBadRequest,
+			wantBody:    `{"error":"Invalid request body"}`,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody *bytes.Buffer
+			if s, ok := tt.requestBody.(string); ok {
+				reqBody = bytes.NewBufferString(s)
+			} else {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(fiber.MethodPost, "/templates", reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantBody != "" {
+				bodyBytes, _ := io.ReadAll(resp.Body)
+				assert.JSONEq(t, tt.wantBody, string(bodyBytes))
+			}
+		})
+	}
+}
+
+func TestTemplateHandler_TemplateByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	app := fiber.New()
+	mockService := NewMockTemplateService(ctrl)
+	handler := NewTemplateHandler(mockService)
+	handler.RegisterRoutes(app)
+
+	templateID := primitive.NewObjectID()
+	template := &MessageTemplate{ID: templateID, Name: "otp", Content: "Code: {{code}}", Variables: []string{"code"}}
+	updateRequest := TemplateRequest{Name: "otp", Content: "Code: {{code}}"}
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		requestBody interface{}
+		wantStatus  int
+		beforeSuite func()
+	}{
+		{
+			name:       "should return template with status 200",
+			method:     fiber.MethodGet,
+			url:        "/templates/" + templateID.Hex(),
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(template, nil)
+			},
+		},
+		{
+			name:       "should return error with status 404 when template is not found",
+			method:     fiber.MethodGet,
+			url:        "/templates/" + templateID.Hex(),
+			wantStatus: fiber.StatusNotFound,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(nil, ErrDocumentNotFound)
+			},
+		},
+		{
+			name:        "should update template with status 200",
+			method:      fiber.MethodPut,
+			url:         "/templates/" + templateID.Hex(),
+			requestBody: updateRequest,
+			wantStatus:  fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().UpdateTemplate(gomock.Any(), templateID, updateRequest).Return(template, nil)
+			},
+		},
+		{
+			name:       "should delete template with status 204",
+			method:     fiber.MethodDelete,
+			url:        "/templates/" + templateID.Hex(),
+			wantStatus: fiber.StatusNoContent,
+			beforeSuite: func() {
+				mockService.EXPECT().DeleteTemplate(gomock.Any(), templateID).Return(nil)
+			},
+		},
+		{
+			name:       "should return templates with status 200",
+			method:     fiber.MethodGet,
+			url:        "/templates",
+			wantStatus: fiber.StatusOK,
+			beforeSuite: func() {
+				mockService.EXPECT().RetrieveTemplates(gomock.Any()).Return([]MessageTemplate{*template}, nil)
+			},
+		},
+		{
+			name:        "should return error with status 400 for invalid ID",
+			method:      fiber.MethodDelete,
+			url:         "/templates/not-an-id",
+			wantStatus:  fiber.StatusBadRequest,
+			beforeSuite: func() {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+
+			var reqBody io.Reader
+			if tt.requestBody != nil {
+				jsonBody, err := json.Marshal(tt.requestBody)
+				assert.NoError(t, err)
+				reqBody = bytes.NewBuffer(jsonBody)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.url, reqBody)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			defer resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrTemplateNameTaken = errors.New("template name is already taken")
+
+type TemplateRepositoryImpl struct {
+	templateCollection *mongo.Collection
+}
+
+func NewTemplateRepositoryImpl(collection *mongo.Collection) *TemplateRepositoryImpl {
+	return &TemplateRepositoryImpl{
+		templateCollection: collection,
+	}
+}
+
+// EnsureIndexes creates the unique index on template names.
+func (tr *TemplateRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	_, err := tr.templateCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return err
+}
+
+func (tr *TemplateRepositoryImpl) InsertTemplate(ctx context.Context, template *MessageTemplate) (primitive.ObjectID, error) {
+	result, err := tr.templateCollection.InsertOne(ctx, template)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return primitive.NilObjectID, ErrTemplateNameTaken
+		}
+		return primitive.NilObjectID, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, ErrInvalidMessageID
+	}
+
+	return id, nil
+}
+
+func (tr *TemplateRepositoryImpl) RetrieveTemplates(ctx context.Context) ([]MessageTemplate, error) {
+	cursor, err := tr.templateCollection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer cursor.Close(ctx)
+
+	var templates []MessageTemplate
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, ErrDocumentDecodingFailed
+	}
+
+	return templates, nil
+}
+
+func (tr *TemplateRepositoryImpl) RetrieveTemplate(ctx context.Context, templateID primitive.ObjectID) (*MessageTemplate, error) {
+	var template MessageTemplate
+	err := tr.templateCollection.FindOne(ctx, bson.M{"_id": templateID}).Decode(&template)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (tr *TemplateRepositoryImpl) UpdateTemplate(ctx context.Context, template *MessageTemplate) error {
+	filter := bson.M{
+		"_id": template.ID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"name":       template.Name,
+			"content":    template.Content,
+			"variables":  template.Variables,
+			"updated_at": template.UpdatedAt,
+		},
+	}
+
+	result, err := tr.templateCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrTemplateNameTaken
+		}
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (tr *TemplateRepositoryImpl) DeleteTemplate(ctx context.Context, templateID primitive.ObjectID) error {
+	result, err := tr.templateCollection.DeleteOne(ctx, bson.M{"_id": templateID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
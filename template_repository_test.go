@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const testTemplateCollection = "templates"
+
+func TestTemplateRepository_CRUD(t *testing.T) {
+	client, cleanFunc, err := prepareTestMongoStore()
+	assert.NoError(t, err)
+
+	defer client.Disconnect(context.Background())
+	defer cleanFunc()
+
+	templateRepository := NewTemplateRepositoryImpl(client.Database(testDB).Collection(testTemplateCollection))
+	assert.NoError(t, templateRepository.EnsureIndexes(context.Background()))
+
+	now := time.Date(2025, 5, 10, 9, 0, 0, 0, time.UTC)
+	template := &MessageTemplate{Name: "otp", Content: "Code: {{code}}", Variables: []string{"code"}, CreatedAt: now, UpdatedAt: now}
+
+	id, err := templateRepository.InsertTemplate(context.Background(), template)
+	assert.NoError(t, err)
+
+	_, err = templateRepository.InsertTemplate(context.Background(), &MessageTemplate{Name: "otp", Content: "Other"})
+	assert.Equal(t, ErrTemplateNameTaken, err)
+
+	template.ID = id
+	template.Content = "Code: {{code}} for {{minutes}} minutes"
+	template.Variables = []string{"code", "minutes"}
+	assert.NoError(t, templateRepository.UpdateTemplate(context.Background(), template))
+
+	got, err := templateRepository.RetrieveTemplate(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, template, got)
+
+	templates, err := templateRepository.RetrieveTemplates(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, templates, 1)
+
+	assert.NoError(t, templateRepository.DeleteTemplate(context.Background(), id))
+	assert.Equal(t, mongo.ErrNoDocuments, templateRepository.DeleteTemplate(context.Background(), id))
+
+	_, err = templateRepository.RetrieveTemplate(context.Background(), primitive.NewObjectID())
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+}
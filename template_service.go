@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvalidTemplateID     = errors.New("invalid template ID")
+	ErrMissingTemplateValues = errors.New("missing template variables")
+)
+
+// templatePlaceholder matches a named placeholder such as {{code}} or {{ name }}.
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+type TemplateRepository interface {
+	InsertTemplate(ctx context.Context, template *MessageTemplate) (primitive.ObjectID, error)
+	RetrieveTemplates(ctx context.Context) ([]MessageTemplate, error)
+	RetrieveTemplate(ctx context.Context, templateID primitive.ObjectID) (*MessageTemplate, error)
+	UpdateTemplate(ctx context.Context, template *MessageTemplate) error
+	DeleteTemplate(ctx context.Context, templateID primitive.ObjectID) error
+}
+
+type TemplateServiceImpl struct {
+	templateRepository TemplateRepository
+	validate           *validator.Validate
+}
+
+func NewTemplateServiceImpl(tr TemplateRepository, validate *validator.Validate) *TemplateServiceImpl {
+	return &TemplateServiceImpl{
+		templateRepository: tr,
+		validate:           validate,
+	}
+}
+
+// TemplateVariables returns the distinct placeholder names used in content, in
+// order of first appearance.
+func TemplateVariables(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// RenderTemplate replaces every placeholder in content with its value. All
+// placeholders must have a value; unused values are ignored.
+func RenderTemplate(content string, values map[string]string) (string, error) {
+	var missing []string
+	for _, name := range TemplateVariables(content) {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("%w: %s", ErrMissingTemplateValues, strings.Join(missing, ", "))
+	}
+
+	return templatePlaceholder.ReplaceAllStringFunc(content, func(placeholder string) string {
+		return values[templatePlaceholder.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
+
+func (ts *TemplateServiceImpl) CreateTemplate(ctx context.Context, req TemplateRequest) (primitive.ObjectID, error) {
+	now := time.Now()
+	template := &MessageTemplate{
+		Name:      req.Name,
+		Content:   req.Content,
+		Variables: TemplateVariables(req.Content),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := ts.validate.Struct(template); err != nil {
+		return primitive.NilObjectID, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+
+	id, err := ts.templateRepository.InsertTemplate(ctx, template)
+	if err != nil {
+		return primitive.NilObjectID, ts.mapRepositoryError(err)
+	}
+
+	return id, nil
+}
+
+func (ts *TemplateServiceImpl) RetrieveTemplates(ctx context.Context) ([]MessageTemplate, error) {
+	templates, err := ts.templateRepository.RetrieveTemplates(ctx)
+	if err != nil {
+		return nil, ErrInternalServerError
+	}
+
+	if templates == nil {
+		templates = []MessageTemplate{}
+	}
+
+	return templates, nil
+}
+
+func (ts *TemplateServiceImpl) RetrieveTemplate(ctx context.Context, templateID primitive.ObjectID) (*MessageTemplate, error) {
+	template, err := ts.templateRepository.RetrieveTemplate(ctx, templateID)
+	if err != nil {
+		return nil, ts.mapRepositoryError(err)
+	}
+
+	return template, nil
+}
+
+// UpdateTemplate replaces the name and content of a template. Messages that
+// reference it and have not been dispatched yet are rendered with the new
+// content.
+func (ts *TemplateServiceImpl) UpdateTemplate(ctx context.Context, templateID primitive.ObjectID, req TemplateRequest) (*MessageTemplate, error) {
+	template, err := ts.RetrieveTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Content = req.Content
+	template.Variables = TemplateVariables(req.Content)
+	template.UpdatedAt = time.Now()
+
+	if err := ts.validate.Struct(template); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidationFailed, err.Error())
+	}
+
+	if err := ts.templateRepository.UpdateTemplate(ctx, template); err != nil {
+		return nil, ts.mapRepositoryError(err)
+	}
+
+	return template, nil
+}
+
+func (ts *TemplateServiceImpl) DeleteTemplate(ctx context.Context, templateID primitive.ObjectID) error {
+	if err := ts.templateRepository.DeleteTemplate(ctx, templateID); err != nil {
+		return ts.mapRepositoryError(err)
+	}
+
+	return nil
+}
+
+func (ts *TemplateServiceImpl) mapRepositoryError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrDocumentNotFound
+	}
+	if errors.Is(err, ErrTemplateNameTaken) {
+		return ErrTemplateNameTaken
+	}
+	return ErrInternalServerError
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		values  map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "should replace every placeholder",
+			content: "Hi {{name}}, your verification code is: {{ code }}. Bye {{name}}!",
+			values:  map[string]string{"name": "Ada", "code": "729384", "unused": "x"},
+			want:    "Hi Ada, your verification code is: 729384. Bye Ada!",
+		},
+		{
+			name:    "should return content without placeholders as is",
+			content: "Static text",
+			values:  nil,
+			want:    "Static text",
+		},
+		{
+			name:    "should report missing variables",
+			content: "Hi {{name}}, your code is {{code}}",
+			values:  map[string]string{},
+			wantErr: "missing template variables: code, name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(tt.content, tt.values)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrMissingTemplateValues)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	assert.Equal(t, []string{"name", "code"}, TemplateVariables("Hi {{name}}, {{code}} is for {{ name }}"))
+	assert.Nil(t, TemplateVariables("no placeholders"))
+}
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockTemplateRepository(ctrl)
+	service := NewTemplateServiceImpl(mockRepo, newTestValidator())
+
+	createdID := primitive.NewObjectID()
+	validRequest := TemplateRequest{Name: "otp", Content: "Your verification code is: {{code}}"}
+
+	tests := []struct {
+		name        string
+		req         TemplateRequest
+		wantID      primitive.ObjectID
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should create template with its variables",
+			req:     validRequest,
+			wantID:  createdID,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertTemplate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, template *MessageTemplate) (primitive.ObjectID, error) {
+					assert.Equal(t, "otp", template.Name)
+					assert.Equal(t, []string{"code"}, template.Variables)
+					assert.False(t, template.CreatedAt.IsZero())
+					return createdID, nil
+				})
+			},
+		},
+		{
+			name:        "should return validation error when name is empty",
+			req:         TemplateRequest{Content: "Hello"},
+			wantID:      primitive.NilObjectID,
+			wantErr:     ErrValidationFailed,
+			beforeSuite: func() {},
+		},
+		{
+			name:    "should return conflict error when name is taken",
+			req:     validRequest,
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrTemplateNameTaken,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertTemplate(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, ErrTemplateNameTaken)
+			},
+		},
+		{
+			name:    "should return internal error when repository fails",
+			req:     validRequest,
+			wantID:  primitive.NilObjectID,
+			wantErr: ErrInternalServerError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().InsertTemplate(gomock.Any(), gomock.Any()).Return(primitive.NilObjectID, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := service.CreateTemplate(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantID, got)
+		})
+	}
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockTemplateRepository(ctrl)
+	service := NewTemplateServiceImpl(mockRepo, newTestValidator())
+
+	templateID := primitive.NewObjectID()
+	validRequest := TemplateRequest{Name: "otp", Content: "Code: {{code}}, valid for {{minutes}} minutes"}
+
+	tests := []struct {
+		name        string
+		req         TemplateRequest
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "should update template content and variables",
+			req:     validRequest,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(&MessageTemplate{ID: templateID, Name: "otp", Content: "Code: {{code}}"}, nil)
+				mockRepo.EXPECT().UpdateTemplate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, template *MessageTemplate) error {
+					assert.Equal(t, validRequest.Content, template.Content)
+					assert.Equal(t, []string{"code", "minutes"}, template.Variables)
+					return nil
+				})
+			},
+		},
+		{
+			name:    "should return not found when template is missing",
+			req:     validRequest,
+			wantErr: ErrDocumentNotFound,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:    "should return validation error when content is empty",
+			req:     TemplateRequest{Name: "otp"},
+			wantErr: ErrValidationFailed,
+			beforeSuite: func() {
+				mockRepo.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(&MessageTemplate{ID: templateID, Name: "otp", Content: "Code: {{code}}"}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			_, err := service.UpdateTemplate(context.Background(), templateID, tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTemplateService_DeleteTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockTemplateRepository(ctrl)
+	service := NewTemplateServiceImpl(mockRepo, newTestValidator())
+
+	templateID := primitive.NewObjectID()
+
+	mockRepo.EXPECT().DeleteTemplate(gomock.Any(), templateID).Return(nil)
+	assert.NoError(t, service.DeleteTemplate(context.Background(), templateID))
+
+	mockRepo.EXPECT().DeleteTemplate(gomock.Any(), templateID).Return(mongo.ErrNoDocuments)
+	assert.Equal(t, ErrDocumentNotFound, service.DeleteTemplate(context.Background(), templateID))
+}
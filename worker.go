@@ -2,6 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
 	"sync"
 	"time"
 
@@ -13,13 +19,99 @@ import (
 )
 
 type WorkerMessageStore interface {
-	FetchAndMarkProcessing(ctx context.Context) (*Message, error)
+	FetchAndMarkProcessing(ctx context.Context, workerID string, priority string) (*Message, error)
+	MarkAsDispatching(ctx context.Context, message *Message) error
 	MarkAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error
-	MarkAsFailed(ctx context.Context, messageID primitive.ObjectID, reason string) error
+	MarkAsFailed(ctx context.Context, message *Message, failure DeliveryFailure) error
+	MarkAsStuck(ctx context.Context, message *Message, reason string) error
+	MarkAsInvalidContent(ctx context.Context, message *Message, reason string) error
+	MarkAsExpired(ctx context.Context, message *Message) error
+	MarkAsSuppressed(ctx context.Context, message *Message, reason string) error
+	MarkAsDuplicate(ctx context.Context, message *Message, originalID primitive.ObjectID) error
+	DeferMessage(ctx context.Context, message *Message, until time.Time) error
+	ScheduleRetry(ctx context.Context, message *Message, nextAttemptAt time.Time, failure DeliveryFailure) error
+	RecordAttempt(ctx context.Context, messageID primitive.ObjectID, attempt DeliveryAttempt) error
+	RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error)
+}
+
+const (
+	FailureClassRetryable = "retryable"
+	FailureClassPermanent = "permanent"
+	FailureClassInvalid   = "invalid"
+)
+
+// DeliveryFailure describes why a delivery attempt failed and how the failure
+// was classified, so it can be stored on the message.
+type DeliveryFailure struct {
+	Reason     string
+	Class      string
+	StatusCode int
+}
+
+func newWebhookFailure(err error) DeliveryFailure {
+	failure := DeliveryFailure{
+		Reason: "failed to send webhook: " + err.Error(),
+		Class:  FailureClassPermanent,
+	}
+
+	if client.IsRetryable(err) {
+		failure.Class = FailureClassRetryable
+	}
+
+	var webhookErr *client.WebhookError
+	if errors.As(err, &webhookErr) {
+		failure.StatusCode = webhookErr.StatusCode
+	}
+
+	return failure
+}
+
+type WorkerTemplateStore interface {
+	RetrieveTemplate(ctx context.Context, templateID primitive.ObjectID) (*MessageTemplate, error)
+}
+
+// WorkerSuppressionList reports why a phone number must not receive messages,
+// or an empty reason when it may.
+type WorkerSuppressionList interface {
+	SuppressionReason(ctx context.Context, phoneNumber string) (string, error)
+}
+
+// WorkerContentFilter reports the name of the banned-content rule a message
+// breaks, if any.
+type WorkerContentFilter interface {
+	Match(content string) (string, bool)
 }
 
 type WorkerMessageCache interface {
 	Set(ctx context.Context, key string, value string) error
+	SetWithTTL(ctx context.Context, key string, value string, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	Del(ctx context.Context, key string) error
+}
+
+// sendGuardKey is held while a webhook call for the message may be in flight.
+func sendGuardKey(messageID primitive.ObjectID) string {
+	return "send-guard:" + messageID.Hex()
+}
+
+// sentRecordKey maps a delivered message to the provider's message ID.
+func sentRecordKey(messageID primitive.ObjectID) string {
+	return "sent:" + messageID.Hex()
+}
+
+// defaultSendGuardTTL is used when worker.sendGuardTTL is not set.
+const defaultSendGuardTTL = 24 * time.Hour
+
+// duplicateRecheckDelay is how long a duplicate waits before it checks again
+// whether the message holding its dedupe key was sent.
+const duplicateRecheckDelay = 30 * time.Second
+
+// dedupeKey is held for the dedupe window by the first message with the given
+// content to the given recipient, and stores that message's ID.
+func dedupeKey(recipientPhoneNumber, content string) string {
+	sum := sha256.Sum256([]byte(recipientPhoneNumber + "\x00" + content))
+	return "dedupe:" + hex.EncodeToString(sum[:])
 }
 
 type WebhookClient interface {
@@ -28,27 +120,78 @@ type WebhookClient interface {
 
 type WorkerConfig struct {
 	WorkerJobInterval time.Duration `mapstructure:"workerJobInterval"`
+	Retry             RetryPolicy   `mapstructure:"retry"`
+	// SendGuardTTL is how long the send guard of a message is kept. The guard
+	// is not deleted after a successful send, so a non-positive value falls
+	// back to defaultSendGuardTTL instead of keeping it forever.
+	SendGuardTTL time.Duration `mapstructure:"sendGuardTTL"`
+	// LowPriorityShare is the fraction of claims, e.g. 0.1 for every tenth,
+	// that prefer low priority messages so they are not starved by a steady
+	// stream of higher priority ones. Zero disables the guard.
+	LowPriorityShare float64 `mapstructure:"lowPriorityShare"`
+	// DedupeWindow is how long after a message is sent another message with
+	// the same content to the same recipient is held back as a duplicate. The
+	// dedupe key is taken right before the dispatch and its expiry restarts once
+	// the send succeeds, so retries do not shorten the window. Zero disables
+	// duplicate suppression.
+	DedupeWindow time.Duration `mapstructure:"dedupeWindow"`
+}
+
+// RetryPolicy controls how failed webhook sends are retried. A message is
+// attempted at most MaxAttempts times before it is marked as failed.
+type RetryPolicy struct {
+	MaxAttempts int           `mapstructure:"maxAttempts"`
+	BaseDelay   time.Duration `mapstructure:"baseDelay"`
+	Multiplier  float64       `mapstructure:"multiplier"`
+	Jitter      float64       `mapstructure:"jitter"` // fraction of the delay, e.g. 0.2 for ±20%
+}
+
+// Backoff returns the delay before the next attempt after the given number of
+// attempts: BaseDelay * Multiplier^(attempt-1), randomized by Jitter.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(multiplier, float64(max(attempt-1, 0)))
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
 }
 
 type WorkerInstance struct {
-	ID                 string
-	workerMessageStore WorkerMessageStore
-	webhookClient      WebhookClient
-	workerMessageCache WorkerMessageCache
-	config             WorkerConfig
-	validate           *validator.Validate
-	logger             *zap.Logger
+	ID                    string
+	workerMessageStore    WorkerMessageStore
+	workerTemplateStore   WorkerTemplateStore
+	workerSuppressionList WorkerSuppressionList
+	contentFilter         WorkerContentFilter
+	webhookClient         WebhookClient
+	workerMessageCache    WorkerMessageCache
+	config                WorkerConfig
+	validate              *validator.Validate
+	logger                *zap.Logger
+	claims                int
 }
 
-func NewWorkerInstance(id string, workerMessageStore WorkerMessageStore, webhookClient WebhookClient, workerMessageCache WorkerMessageCache, config WorkerConfig, logger *zap.Logger, validate *validator.Validate) *WorkerInstance {
+func NewWorkerInstance(id string, workerMessageStore WorkerMessageStore, workerTemplateStore WorkerTemplateStore, workerSuppressionList WorkerSuppressionList, contentFilter WorkerContentFilter, webhookClient WebhookClient, workerMessageCache WorkerMessageCache, config WorkerConfig, logger *zap.Logger, validate *validator.Validate) *WorkerInstance {
+	if config.SendGuardTTL <= 0 {
+		config.SendGuardTTL = defaultSendGuardTTL
+	}
+
 	return &WorkerInstance{
-		ID:                 id,
-		workerMessageStore: workerMessageStore,
-		workerMessageCache: workerMessageCache,
-		webhookClient:      webhookClient,
-		config:             config,
-		validate:           validate,
-		logger:             logger.With(zap.String("component", "worker"), zap.String("worker_id", id)),
+		ID:                    id,
+		workerMessageStore:    workerMessageStore,
+		workerTemplateStore:   workerTemplateStore,
+		workerSuppressionList: workerSuppressionList,
+		contentFilter:         contentFilter,
+		workerMessageCache:    workerMessageCache,
+		webhookClient:         webhookClient,
+		config:                config,
+		validate:              validate,
+		logger:                logger.With(zap.String("component", "worker"), zap.String("worker_id", id)),
 	}
 }
 
@@ -89,7 +232,7 @@ func (w *WorkerInstance) Start(ctx context.Context, wg *sync.WaitGroup, canFetch
 }
 
 func (w *WorkerInstance) ProcessMessage(ctx context.Context) (bool, error) {
-	message, err := w.workerMessageStore.FetchAndMarkProcessing(ctx)
+	message, err := w.claimMessage(ctx)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return false, nil
@@ -99,9 +242,32 @@ func (w *WorkerInstance) ProcessMessage(ctx context.Context) (bool, error) {
 
 	w.logger.Info("Processing message", zap.String("message_id", message.ID.Hex()))
 
+	if message.ExpiresAt != nil && !time.Now().Before(*message.ExpiresAt) {
+		return true, w.markExpired(ctx, message)
+	}
+
+	if message.DeliveryWindow != nil {
+		if deferred, err := w.deferOutsideWindow(ctx, message); deferred || err != nil {
+			return true, err
+		}
+	}
+
+	if message.TemplateID != nil {
+		if rendered, err := w.renderTemplate(ctx, message); !rendered || err != nil {
+			return true, err
+		}
+	}
+
 	if err := w.validate.Struct(message); err != nil {
 		w.logger.Error("Invalid message struct", zap.String("message_id", message.ID.Hex()), zap.Error(err))
-		if err := w.workerMessageStore.MarkAsFailed(ctx, message.ID, "invalid message struct: "+err.Error()); err != nil {
+		failure := DeliveryFailure{
+			Reason: "invalid message struct: " + err.Error(),
+			Class:  FailureClassInvalid,
+		}
+		if err := w.workerMessageStore.MarkAsFailed(ctx, message, failure); err != nil {
+			if w.leaseLost(message, err) {
+				return true, nil
+			}
 			w.logger.Error("Failed to mark message as failed",
 				zap.String("message_id", message.ID.Hex()),
 				zap.Error(err))
@@ -110,24 +276,151 @@ func (w *WorkerInstance) ProcessMessage(ctx context.Context) (bool, error) {
 		return true, err
 	}
 
+	if rule, matched := w.contentFilter.Match(message.Content); matched {
+		return true, w.markInvalidContent(ctx, message, contentFilterReason(rule))
+	}
+
+	if sent, err := w.reconcileEarlierSend(ctx, message); sent || err != nil {
+		return true, err
+	}
+
+	reason, err := w.workerSuppressionList.SuppressionReason(ctx, message.RecipientPhoneNumber)
+	if err != nil {
+		w.logger.Error("Failed to check suppression list",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return true, err
+	}
+
+	if reason != "" {
+		w.logger.Info("Recipient is suppressed, not sending message",
+			zap.String("message_id", message.ID.Hex()),
+			zap.String("reason", reason))
+		if err := w.workerMessageStore.MarkAsSuppressed(ctx, message, "recipient suppressed: "+reason); err != nil {
+			if w.leaseLost(message, err) {
+				return true, nil
+			}
+			w.logger.Error("Failed to mark message as suppressed",
+				zap.String("message_id", message.ID.Hex()),
+				zap.Error(err))
+			return true, err
+		}
+		return true, nil
+	}
+
+	if w.config.DedupeWindow > 0 {
+		if duplicate, err := w.markIfDuplicate(ctx, message); duplicate || err != nil {
+			return true, err
+		}
+	}
+
+	if err := w.workerMessageStore.MarkAsDispatching(ctx, message); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			w.logger.Warn("Lease lost before dispatch, skipping message", zap.String("message_id", message.ID.Hex()))
+			return true, nil
+		}
+		w.logger.Error("Failed to mark message as dispatching",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return true, err
+	}
+
+	// The guard is only taken once the dispatch is recorded. A worker dying
+	// before that leaves no guard behind, so the reaper can return the message
+	// to unsent and the next attempt is not mistaken for a possible resend.
+	acquired, err := w.workerMessageCache.SetNX(ctx, sendGuardKey(message.ID), w.ID, w.config.SendGuardTTL)
+	if err != nil {
+		w.logger.Error("Failed to acquire send guard",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return true, w.retryUndispatched(ctx, message, DeliveryFailure{
+			Reason: "failed to acquire send guard: " + err.Error(),
+			Class:  FailureClassRetryable,
+		})
+	}
+
+	if !acquired {
+		// An earlier attempt took the guard and never recorded a send or released
+		// it, so it may have reached the webhook. Do not risk a second SMS.
+		w.logger.Warn("Send guard already held, marking message as stuck", zap.String("message_id", message.ID.Hex()))
+		if err := w.workerMessageStore.MarkAsStuck(ctx, message, "send guard held by an earlier attempt"); err != nil {
+			if w.leaseLost(message, err) {
+				return true, nil
+			}
+			w.logger.Error("Failed to mark message as stuck",
+				zap.String("message_id", message.ID.Hex()),
+				zap.Error(err))
+			return true, err
+		}
+		return true, nil
+	}
+
+	attemptedAt := time.Now()
 	res, err := w.webhookClient.PostMessage(ctx, &client.WebhookRequest{
-		To:      message.RecipientPhoneNumber,
-		Content: message.Content,
+		To:         message.RecipientPhoneNumber,
+		Content:    message.Content,
+		ExternalID: message.ExternalID,
+		Tags:       message.Tags,
+		Metadata:   message.Metadata,
 	})
+	w.recordAttempt(ctx, message.ID, attemptedAt, res, err)
 	if err != nil {
+		if !client.IsUndelivered(err) {
+			return true, w.markPossiblyDelivered(ctx, message, err)
+		}
+
+		w.releaseSendGuard(ctx, message.ID)
+
+		failure := newWebhookFailure(err)
 		w.logger.Error("Failed to send message to webhook",
 			zap.String("message_id", message.ID.Hex()),
+			zap.Int("attempt", message.Attempts),
+			zap.String("failure_class", failure.Class),
 			zap.Error(err))
-		if err := w.workerMessageStore.MarkAsFailed(ctx, message.ID, "failed to send webhook: "+err.Error()); err != nil {
+
+		if failure.Class == FailureClassRetryable && message.Attempts < w.config.Retry.MaxAttempts {
+			delay := w.config.Retry.Backoff(message.Attempts)
+			var webhookErr *client.WebhookError
+			if errors.As(err, &webhookErr) && webhookErr.RetryAfter > delay {
+				delay = webhookErr.RetryAfter
+			}
+
+			if err := w.workerMessageStore.ScheduleRetry(ctx, message, time.Now().Add(delay), failure); err != nil {
+				if w.leaseLost(message, err) {
+					return true, nil
+				}
+				w.logger.Error("Failed to schedule message retry",
+					zap.String("message_id", message.ID.Hex()),
+					zap.Error(err))
+				return true, err
+			}
+
+			return true, err
+		}
+
+		if err := w.workerMessageStore.MarkAsFailed(ctx, message, failure); err != nil {
+			if w.leaseLost(message, err) {
+				return true, nil
+			}
 			w.logger.Error("Failed to mark message as failed",
 				zap.String("message_id", message.ID.Hex()),
 				zap.Error(err))
 			return true, err
 		}
 
+		if w.config.DedupeWindow > 0 {
+			w.releaseDedupeKey(ctx, message)
+		}
+
 		return true, err
 	}
 
+	if err := w.workerMessageCache.Set(ctx, sentRecordKey(message.ID), res.MessageID); err != nil {
+		w.logger.Error("Failed to record provider message ID",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+	}
+
 	now := time.Now()
 	if err := w.workerMessageStore.MarkAsSent(ctx, message.ID, res.MessageID); err != nil {
 		w.logger.Error("Failed to mark message as sent",
@@ -136,6 +429,10 @@ func (w *WorkerInstance) ProcessMessage(ctx context.Context) (bool, error) {
 		return true, err
 	}
 
+	if w.config.DedupeWindow > 0 {
+		w.refreshDedupeKey(ctx, message)
+	}
+
 	if err := w.workerMessageCache.Set(ctx, res.MessageID, now.Format(time.RFC3339)); err != nil {
 		w.logger.Error("Failed to cache message ID",
 			zap.String("message_id", message.ID.Hex()),
@@ -146,3 +443,403 @@ func (w *WorkerInstance) ProcessMessage(ctx context.Context) (bool, error) {
 	w.logger.Info("Message processed successfully", zap.String("message_id", message.ID.Hex()))
 	return true, nil
 }
+
+// claimMessage claims the next message by priority. Every claim picked by the
+// starvation guard tries low priority messages first and falls back to the
+// regular order when none are due.
+func (w *WorkerInstance) claimMessage(ctx context.Context) (*Message, error) {
+	w.claims++
+
+	if w.preferLowPriority() {
+		message, err := w.workerMessageStore.FetchAndMarkProcessing(ctx, w.ID, PriorityLow)
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return message, err
+		}
+	}
+
+	return w.workerMessageStore.FetchAndMarkProcessing(ctx, w.ID, "")
+}
+
+func (w *WorkerInstance) preferLowPriority() bool {
+	if w.config.LowPriorityShare <= 0 {
+		return false
+	}
+
+	every := max(int(math.Round(1/w.config.LowPriorityShare)), 1)
+	return w.claims%every == 0
+}
+
+// leaseLost reports whether a transition of a claimed message matched nothing
+// because this worker no longer holds its lease, e.g. the reaper released it or
+// another worker claimed it again. The message is then left to its current
+// holder instead of being reported as an error.
+func (w *WorkerInstance) leaseLost(message *Message, err error) bool {
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return false
+	}
+
+	w.logger.Warn("Lease lost, leaving message to its current holder", zap.String("message_id", message.ID.Hex()))
+	return true
+}
+
+func (w *WorkerInstance) markExpired(ctx context.Context, message *Message) error {
+	w.logger.Warn("Message expired before delivery",
+		zap.String("message_id", message.ID.Hex()),
+		zap.Time("expires_at", *message.ExpiresAt))
+
+	if err := w.workerMessageStore.MarkAsExpired(ctx, message); err != nil {
+		if w.leaseLost(message, err) {
+			return nil
+		}
+		w.logger.Error("Failed to mark message as expired",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// deferOutsideWindow returns the message to the queue until its delivery window
+// opens, and reports whether it did. A message whose window only opens after
+// it expires is expired right away.
+func (w *WorkerInstance) deferOutsideWindow(ctx context.Context, message *Message) (bool, error) {
+	now := time.Now()
+	opening, err := message.DeliveryWindow.NextOpening(now)
+	if err != nil {
+		failure := DeliveryFailure{
+			Reason: "invalid delivery window: " + err.Error(),
+			Class:  FailureClassInvalid,
+		}
+		if err := w.workerMessageStore.MarkAsFailed(ctx, message, failure); err != nil {
+			if w.leaseLost(message, err) {
+				return true, nil
+			}
+			w.logger.Error("Failed to mark message as failed",
+				zap.String("message_id", message.ID.Hex()),
+				zap.Error(err))
+			return true, err
+		}
+		return true, nil
+	}
+
+	if opening.Equal(now) {
+		return false, nil
+	}
+
+	if message.ExpiresAt != nil && !opening.Before(*message.ExpiresAt) {
+		return true, w.markExpired(ctx, message)
+	}
+
+	w.logger.Info("Message is outside its delivery window, deferring",
+		zap.String("message_id", message.ID.Hex()),
+		zap.Time("deferred_until", opening))
+
+	if err := w.workerMessageStore.DeferMessage(ctx, message, opening); err != nil {
+		if w.leaseLost(message, err) {
+			return true, nil
+		}
+		w.logger.Error("Failed to defer message",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return true, err
+	}
+
+	return true, nil
+}
+
+// renderTemplate replaces the content of a template message with the rendered
+// template. When the template cannot be rendered, or the result breaks the
+// content rules, the message is marked invalid_content and false is returned.
+func (w *WorkerInstance) renderTemplate(ctx context.Context, message *Message) (bool, error) {
+	template, err := w.workerTemplateStore.RetrieveTemplate(ctx, *message.TemplateID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, w.markInvalidContent(ctx, message, "template not found")
+		}
+		w.logger.Error("Failed to retrieve message template",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return false, err
+	}
+
+	content, err := RenderTemplate(template.Content, message.TemplateVariables)
+	if err != nil {
+		return false, w.markInvalidContent(ctx, message, err.Error())
+	}
+
+	message.Content = content
+	message.Encoding, message.Segments = MeasureSegments(content)
+	if err := w.validate.StructPartial(message, "Content"); err != nil {
+		return false, w.markInvalidContent(ctx, message, "rendered template is invalid: "+err.Error())
+	}
+
+	return true, nil
+}
+
+func (w *WorkerInstance) markInvalidContent(ctx context.Context, message *Message, reason string) error {
+	w.logger.Warn("Message content is invalid",
+		zap.String("message_id", message.ID.Hex()),
+		zap.String("reason", reason))
+
+	if err := w.workerMessageStore.MarkAsInvalidContent(ctx, message, reason); err != nil {
+		if w.leaseLost(message, err) {
+			return nil
+		}
+		w.logger.Error("Failed to mark message as invalid content",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// reconcileEarlierSend checks whether an earlier attempt already delivered the
+// message, e.g. when MarkAsSent failed after the webhook accepted it. If so the
+// message is marked as sent with the recorded provider message ID instead of
+// being posted again.
+func (w *WorkerInstance) reconcileEarlierSend(ctx context.Context, message *Message) (bool, error) {
+	providerMessageID, err := w.workerMessageCache.Get(ctx, sentRecordKey(message.ID))
+	if err != nil {
+		w.logger.Error("Failed to look up earlier send",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return false, err
+	}
+
+	if providerMessageID == "" {
+		return false, nil
+	}
+
+	w.logger.Warn("Message was already sent, reconciling status",
+		zap.String("message_id", message.ID.Hex()),
+		zap.String("webhook_message_id", providerMessageID))
+
+	if err := w.workerMessageStore.MarkAsSent(ctx, message.ID, providerMessageID); err != nil {
+		w.logger.Error("Failed to mark message as sent",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return true, err
+	}
+
+	return true, nil
+}
+
+// retryUndispatched schedules another attempt for a message whose dispatch was
+// recorded but whose webhook call was never made. ScheduleRetry clears the
+// dispatch marker, so the reaper does not take the message for a possible send.
+func (w *WorkerInstance) retryUndispatched(ctx context.Context, message *Message, failure DeliveryFailure) error {
+	if err := w.workerMessageStore.ScheduleRetry(ctx, message, time.Now().Add(w.config.Retry.Backoff(message.Attempts)), failure); err != nil {
+		if w.leaseLost(message, err) {
+			return nil
+		}
+		w.logger.Error("Failed to schedule message retry",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return err
+	}
+
+	return errors.New(failure.Reason)
+}
+
+// markPossiblyDelivered handles a webhook call that failed after the request may
+// have reached the webhook, e.g. a timeout. The send guard is kept and the
+// message is moved to stuck instead of retried, so it is never sent twice.
+func (w *WorkerInstance) markPossiblyDelivered(ctx context.Context, message *Message, postErr error) error {
+	w.logger.Warn("Webhook call may have delivered the message, marking message as stuck",
+		zap.String("message_id", message.ID.Hex()),
+		zap.Error(postErr))
+
+	if err := w.workerMessageStore.MarkAsStuck(ctx, message, "webhook call may have delivered the message: "+postErr.Error()); err != nil {
+		if w.leaseLost(message, err) {
+			return nil
+		}
+		w.logger.Error("Failed to mark message as stuck",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return err
+	}
+
+	return postErr
+}
+
+// releaseSendGuard frees the guard after a webhook call that certainly did not
+// deliver the message: the webhook answered with an error status or the
+// request never left. A retry can then take the guard again.
+func (w *WorkerInstance) releaseSendGuard(ctx context.Context, messageID primitive.ObjectID) {
+	if err := w.workerMessageCache.Del(ctx, sendGuardKey(messageID)); err != nil {
+		w.logger.Error("Failed to release send guard",
+			zap.String("message_id", messageID.Hex()),
+			zap.Error(err))
+	}
+}
+
+// markIfDuplicate takes the dedupe key of the message and reports whether an
+// earlier message already holds it, in which case the message is not sent now.
+// A duplicate of a message that was sent is marked as a duplicate of it, and a
+// duplicate of a message that is still queued or in flight is deferred until
+// that one is done. A stuck holder may have been delivered, so its duplicates
+// are deferred too until an operator resolves it. When the holder ended without
+// being sent, e.g. it expired or was cancelled, the message takes the key over
+// and goes ahead, so the recipient still gets the content once. A retry of the
+// message finds its own ID under the key and goes ahead.
+func (w *WorkerInstance) markIfDuplicate(ctx context.Context, message *Message) (bool, error) {
+	key := dedupeKey(message.RecipientPhoneNumber, message.Content)
+	acquired, err := w.workerMessageCache.SetNX(ctx, key, message.ID.Hex(), w.config.DedupeWindow)
+	if err != nil {
+		w.logger.Error("Failed to acquire dedupe key",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return false, err
+	}
+
+	if acquired {
+		return false, nil
+	}
+
+	holder, err := w.workerMessageCache.Get(ctx, key)
+	if err != nil {
+		w.logger.Error("Failed to look up original message",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return false, err
+	}
+
+	// The key may have expired since SetNX, in which case nothing is held back.
+	originalID, err := primitive.ObjectIDFromHex(holder)
+	if err != nil || originalID == message.ID {
+		return false, nil
+	}
+
+	original, err := w.workerMessageStore.RetrieveMessage(ctx, originalID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		w.logger.Error("Failed to retrieve original message",
+			zap.String("message_id", message.ID.Hex()),
+			zap.String("original_message_id", holder),
+			zap.Error(err))
+		return false, err
+	}
+
+	if original != nil {
+		switch original.Status {
+		case StatusSent:
+			return true, w.markDuplicate(ctx, message, originalID)
+		case StatusUnsent, StatusProcessing, StatusStuck:
+			return true, w.deferDuplicate(ctx, message, originalID)
+		}
+	}
+
+	w.logger.Info("Original message was not sent, taking over its dedupe key",
+		zap.String("message_id", message.ID.Hex()),
+		zap.String("original_message_id", holder))
+
+	if err := w.workerMessageCache.Del(ctx, key); err != nil {
+		w.logger.Error("Failed to release dedupe key",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return false, err
+	}
+
+	acquired, err = w.workerMessageCache.SetNX(ctx, key, message.ID.Hex(), w.config.DedupeWindow)
+	if err != nil {
+		w.logger.Error("Failed to acquire dedupe key",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return false, err
+	}
+
+	if !acquired {
+		// Another duplicate took the key over first; check again once it is done.
+		return true, w.deferDuplicate(ctx, message, originalID)
+	}
+
+	return false, nil
+}
+
+func (w *WorkerInstance) markDuplicate(ctx context.Context, message *Message, originalID primitive.ObjectID) error {
+	w.logger.Info("Duplicate content for recipient, not sending message",
+		zap.String("message_id", message.ID.Hex()),
+		zap.String("original_message_id", originalID.Hex()))
+
+	if err := w.workerMessageStore.MarkAsDuplicate(ctx, message, originalID); err != nil {
+		if w.leaseLost(message, err) {
+			return nil
+		}
+		w.logger.Error("Failed to mark message as duplicate",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (w *WorkerInstance) deferDuplicate(ctx context.Context, message *Message, originalID primitive.ObjectID) error {
+	until := time.Now().Add(duplicateRecheckDelay)
+	w.logger.Info("Duplicate content for recipient is still pending, deferring",
+		zap.String("message_id", message.ID.Hex()),
+		zap.String("original_message_id", originalID.Hex()),
+		zap.Time("deferred_until", until))
+
+	if err := w.workerMessageStore.DeferMessage(ctx, message, until); err != nil {
+		if w.leaseLost(message, err) {
+			return nil
+		}
+		w.logger.Error("Failed to defer message",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// refreshDedupeKey restarts the expiry of the dedupe key of a message that was
+// sent, so the window runs from the send rather than from the first attempt.
+// A failure only shortens the window and is logged.
+func (w *WorkerInstance) refreshDedupeKey(ctx context.Context, message *Message) {
+	if err := w.workerMessageCache.SetWithTTL(ctx, dedupeKey(message.RecipientPhoneNumber, message.Content), message.ID.Hex(), w.config.DedupeWindow); err != nil {
+		w.logger.Error("Failed to refresh dedupe key",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+	}
+}
+
+// releaseDedupeKey frees the dedupe key of a message that failed for good, so
+// the content can be sent to the recipient again within the window.
+func (w *WorkerInstance) releaseDedupeKey(ctx context.Context, message *Message) {
+	if err := w.workerMessageCache.Del(ctx, dedupeKey(message.RecipientPhoneNumber, message.Content)); err != nil {
+		w.logger.Error("Failed to release dedupe key",
+			zap.String("message_id", message.ID.Hex()),
+			zap.Error(err))
+	}
+}
+
+// recordAttempt appends the webhook call to the message history. History is
+// informational, so a failure to write it is logged and does not change the
+// outcome of the delivery.
+func (w *WorkerInstance) recordAttempt(ctx context.Context, messageID primitive.ObjectID, attemptedAt time.Time, res *client.WebhookResponse, postErr error) {
+	attempt := DeliveryAttempt{
+		AttemptedAt: attemptedAt,
+		WorkerID:    w.ID,
+		LatencyMs:   time.Since(attemptedAt).Milliseconds(),
+	}
+
+	if postErr != nil {
+		attempt.Error = postErr.Error()
+		var webhookErr *client.WebhookError
+		if errors.As(postErr, &webhookErr) {
+			attempt.StatusCode = webhookErr.StatusCode
+		}
+	} else {
+		attempt.StatusCode = http.StatusAccepted
+		attempt.ProviderMessageID = res.MessageID
+	}
+
+	if err := w.workerMessageStore.RecordAttempt(ctx, messageID, attempt); err != nil {
+		w.logger.Error("Failed to record delivery attempt",
+			zap.String("message_id", messageID.Hex()),
+			zap.Error(err))
+	}
+}
@@ -12,6 +12,7 @@ package main
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	client "github.com/desxz/go-message-scheduler/client"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -42,33 +43,103 @@ func (m *MockWorkerMessageStore) EXPECT() *MockWorkerMessageStoreMockRecorder {
 	return m.recorder
 }
 
+// DeferMessage mocks base method.
+func (m *MockWorkerMessageStore) DeferMessage(ctx context.Context, message *Message, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeferMessage", ctx, message, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeferMessage indicates an expected call of DeferMessage.
+func (mr *MockWorkerMessageStoreMockRecorder) DeferMessage(ctx, message, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeferMessage", reflect.TypeOf((*MockWorkerMessageStore)(nil).DeferMessage), ctx, message, until)
+}
+
 // FetchAndMarkProcessing mocks base method.
-func (m *MockWorkerMessageStore) FetchAndMarkProcessing(ctx context.Context) (*Message, error) {
+func (m *MockWorkerMessageStore) FetchAndMarkProcessing(ctx context.Context, workerID, priority string) (*Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchAndMarkProcessing", ctx)
+	ret := m.ctrl.Call(m, "FetchAndMarkProcessing", ctx, workerID, priority)
 	ret0, _ := ret[0].(*Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchAndMarkProcessing indicates an expected call of FetchAndMarkProcessing.
-func (mr *MockWorkerMessageStoreMockRecorder) FetchAndMarkProcessing(ctx any) *gomock.Call {
+func (mr *MockWorkerMessageStoreMockRecorder) FetchAndMarkProcessing(ctx, workerID, priority any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAndMarkProcessing", reflect.TypeOf((*MockWorkerMessageStore)(nil).FetchAndMarkProcessing), ctx, workerID, priority)
+}
+
+// MarkAsDispatching mocks base method.
+func (m *MockWorkerMessageStore) MarkAsDispatching(ctx context.Context, message *Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsDispatching", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsDispatching indicates an expected call of MarkAsDispatching.
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsDispatching(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsDispatching", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsDispatching), ctx, message)
+}
+
+// MarkAsDuplicate mocks base method.
+func (m *MockWorkerMessageStore) MarkAsDuplicate(ctx context.Context, message *Message, originalID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsDuplicate", ctx, message, originalID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsDuplicate indicates an expected call of MarkAsDuplicate.
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsDuplicate(ctx, message, originalID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchAndMarkProcessing", reflect.TypeOf((*MockWorkerMessageStore)(nil).FetchAndMarkProcessing), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsDuplicate", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsDuplicate), ctx, message, originalID)
+}
+
+// MarkAsExpired mocks base method.
+func (m *MockWorkerMessageStore) MarkAsExpired(ctx context.Context, message *Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsExpired", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsExpired indicates an expected call of MarkAsExpired.
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsExpired(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsExpired", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsExpired), ctx, message)
 }
 
 // MarkAsFailed mocks base method.
-func (m *MockWorkerMessageStore) MarkAsFailed(ctx context.Context, messageID primitive.ObjectID, reason string) error {
+func (m *MockWorkerMessageStore) MarkAsFailed(ctx context.Context, message *Message, failure DeliveryFailure) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkAsFailed", ctx, messageID, reason)
+	ret := m.ctrl.Call(m, "MarkAsFailed", ctx, message, failure)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MarkAsFailed indicates an expected call of MarkAsFailed.
-func (mr *MockWorkerMessageStoreMockRecorder) MarkAsFailed(ctx, messageID, reason any) *gomock.Call {
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsFailed(ctx, message, failure any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsFailed", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsFailed), ctx, message, failure)
+}
+
+// MarkAsInvalidContent mocks base method.
+func (m *MockWorkerMessageStore) MarkAsInvalidContent(ctx context.Context, message *Message, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsInvalidContent", ctx, message, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsInvalidContent indicates an expected call of MarkAsInvalidContent.
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsInvalidContent(ctx, message, reason any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsFailed", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsFailed), ctx, messageID, reason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsInvalidContent", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsInvalidContent), ctx, message, reason)
 }
 
 // MarkAsSent mocks base method.
@@ -85,6 +156,194 @@ func (mr *MockWorkerMessageStoreMockRecorder) MarkAsSent(ctx, messageID, webhook
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsSent", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsSent), ctx, messageID, webhookMessageID)
 }
 
+// MarkAsStuck mocks base method.
+func (m *MockWorkerMessageStore) MarkAsStuck(ctx context.Context, message *Message, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsStuck", ctx, message, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsStuck indicates an expected call of MarkAsStuck.
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsStuck(ctx, message, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsStuck", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsStuck), ctx, message, reason)
+}
+
+// MarkAsSuppressed mocks base method.
+func (m *MockWorkerMessageStore) MarkAsSuppressed(ctx context.Context, message *Message, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsSuppressed", ctx, message, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAsSuppressed indicates an expected call of MarkAsSuppressed.
+func (mr *MockWorkerMessageStoreMockRecorder) MarkAsSuppressed(ctx, message, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsSuppressed", reflect.TypeOf((*MockWorkerMessageStore)(nil).MarkAsSuppressed), ctx, message, reason)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockWorkerMessageStore) RecordAttempt(ctx context.Context, messageID primitive.ObjectID, attempt DeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", ctx, messageID, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockWorkerMessageStoreMockRecorder) RecordAttempt(ctx, messageID, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockWorkerMessageStore)(nil).RecordAttempt), ctx, messageID, attempt)
+}
+
+// RetrieveMessage mocks base method.
+func (m *MockWorkerMessageStore) RetrieveMessage(ctx context.Context, messageID primitive.ObjectID) (*Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveMessage", ctx, messageID)
+	ret0, _ := ret[0].(*Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveMessage indicates an expected call of RetrieveMessage.
+func (mr *MockWorkerMessageStoreMockRecorder) RetrieveMessage(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveMessage", reflect.TypeOf((*MockWorkerMessageStore)(nil).RetrieveMessage), ctx, messageID)
+}
+
+// ScheduleRetry mocks base method.
+func (m *MockWorkerMessageStore) ScheduleRetry(ctx context.Context, message *Message, nextAttemptAt time.Time, failure DeliveryFailure) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleRetry", ctx, message, nextAttemptAt, failure)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleRetry indicates an expected call of ScheduleRetry.
+func (mr *MockWorkerMessageStoreMockRecorder) ScheduleRetry(ctx, message, nextAttemptAt, failure any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleRetry", reflect.TypeOf((*MockWorkerMessageStore)(nil).ScheduleRetry), ctx, message, nextAttemptAt, failure)
+}
+
+// MockWorkerTemplateStore is a mock of WorkerTemplateStore interface.
+type MockWorkerTemplateStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkerTemplateStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockWorkerTemplateStoreMockRecorder is the mock recorder for MockWorkerTemplateStore.
+type MockWorkerTemplateStoreMockRecorder struct {
+	mock *MockWorkerTemplateStore
+}
+
+// NewMockWorkerTemplateStore creates a new mock instance.
+func NewMockWorkerTemplateStore(ctrl *gomock.Controller) *MockWorkerTemplateStore {
+	mock := &MockWorkerTemplateStore{ctrl: ctrl}
+	mock.recorder = &MockWorkerTemplateStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkerTemplateStore) EXPECT() *MockWorkerTemplateStoreMockRecorder {
+	return m.recorder
+}
+
+// RetrieveTemplate mocks base method.
+func (m *MockWorkerTemplateStore) RetrieveTemplate(ctx context.Context, templateID primitive.ObjectID) (*MessageTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveTemplate", ctx, templateID)
+	ret0, _ := ret[0].(*MessageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveTemplate indicates an expected call of RetrieveTemplate.
+func (mr *MockWorkerTemplateStoreMockRecorder) RetrieveTemplate(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveTemplate", reflect.TypeOf((*MockWorkerTemplateStore)(nil).RetrieveTemplate), ctx, templateID)
+}
+
+// MockWorkerSuppressionList is a mock of WorkerSuppressionList interface.
+type MockWorkerSuppressionList struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkerSuppressionListMockRecorder
+	isgomock struct{}
+}
+
+// MockWorkerSuppressionListMockRecorder is the mock recorder for MockWorkerSuppressionList.
+type MockWorkerSuppressionListMockRecorder struct {
+	mock *MockWorkerSuppressionList
+}
+
+// NewMockWorkerSuppressionList creates a new mock instance.
+func NewMockWorkerSuppressionList(ctrl *gomock.Controller) *MockWorkerSuppressionList {
+	mock := &MockWorkerSuppressionList{ctrl: ctrl}
+	mock.recorder = &MockWorkerSuppressionListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkerSuppressionList) EXPECT() *MockWorkerSuppressionListMockRecorder {
+	return m.recorder
+}
+
+// SuppressionReason mocks base method.
+func (m *MockWorkerSuppressionList) SuppressionReason(ctx context.Context, phoneNumber string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuppressionReason", ctx, phoneNumber)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuppressionReason indicates an expected call of SuppressionReason.
+func (mr *MockWorkerSuppressionListMockRecorder) SuppressionReason(ctx, phoneNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuppressionReason", reflect.TypeOf((*MockWorkerSuppressionList)(nil).SuppressionReason), ctx, phoneNumber)
+}
+
+// MockWorkerContentFilter is a mock of WorkerContentFilter interface.
+type MockWorkerContentFilter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWorkerContentFilterMockRecorder
+	isgomock struct{}
+}
+
+// MockWorkerContentFilterMockRecorder is the mock recorder for MockWorkerContentFilter.
+type MockWorkerContentFilterMockRecorder struct {
+	mock *MockWorkerContentFilter
+}
+
+// NewMockWorkerContentFilter creates a new mock instance.
+func NewMockWorkerContentFilter(ctrl *gomock.Controller) *MockWorkerContentFilter {
+	mock := &MockWorkerContentFilter{ctrl: ctrl}
+	mock.recorder = &MockWorkerContentFilterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWorkerContentFilter) EXPECT() *MockWorkerContentFilterMockRecorder {
+	return m.recorder
+}
+
+// Match mocks base method.
+func (m *MockWorkerContentFilter) Match(content string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", content)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockWorkerContentFilterMockRecorder) Match(content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockWorkerContentFilter)(nil).Match), content)
+}
+
 // MockWorkerMessageCache is a mock of WorkerMessageCache interface.
 type MockWorkerMessageCache struct {
 	ctrl     *gomock.Controller
@@ -109,6 +368,35 @@ func (m *MockWorkerMessageCache) EXPECT() *MockWorkerMessageCacheMockRecorder {
 	return m.recorder
 }
 
+// Del mocks base method.
+func (m *MockWorkerMessageCache) Del(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Del", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Del indicates an expected call of Del.
+func (mr *MockWorkerMessageCacheMockRecorder) Del(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockWorkerMessageCache)(nil).Del), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockWorkerMessageCache) Get(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWorkerMessageCacheMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWorkerMessageCache)(nil).Get), ctx, key)
+}
+
 // Set mocks base method.
 func (m *MockWorkerMessageCache) Set(ctx context.Context, key, value string) error {
 	m.ctrl.T.Helper()
@@ -123,6 +411,35 @@ func (mr *MockWorkerMessageCacheMockRecorder) Set(ctx, key, value any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockWorkerMessageCache)(nil).Set), ctx, key, value)
 }
 
+// SetNX mocks base method.
+func (m *MockWorkerMessageCache) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockWorkerMessageCacheMockRecorder) SetNX(ctx, key, value, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockWorkerMessageCache)(nil).SetNX), ctx, key, value, ttl)
+}
+
+// SetWithTTL mocks base method.
+func (m *MockWorkerMessageCache) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithTTL", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithTTL indicates an expected call of SetWithTTL.
+func (mr *MockWorkerMessageCacheMockRecorder) SetWithTTL(ctx, key, value, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithTTL", reflect.TypeOf((*MockWorkerMessageCache)(nil).SetWithTTL), ctx, key, value, ttl)
+}
+
 // MockWebhookClient is a mock of WebhookClient interface.
 type MockWebhookClient struct {
 	ctrl     *gomock.Controller
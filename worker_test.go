@@ -2,11 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/desxz/go-message-scheduler/client"
-	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -21,8 +22,17 @@ func TestWorker_ProcessMessage(t *testing.T) {
 	mockRepo := NewMockWorkerMessageStore(ctrl)
 	mockWebhookClient := NewMockWebhookClient(ctrl)
 	mockCache := NewMockWorkerMessageCache(ctrl)
+	mockTemplateStore := NewMockWorkerTemplateStore(ctrl)
+	mockSuppressionList := NewMockWorkerSuppressionList(ctrl)
+	mockContentFilter := NewMockWorkerContentFilter(ctrl)
 	config := WorkerConfig{
 		WorkerJobInterval: 1 * time.Second,
+		Retry: RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Second,
+			Multiplier:  2,
+		},
+		SendGuardTTL: 24 * time.Hour,
 	}
 
 	tests := []struct {
@@ -42,13 +52,23 @@ func TestWorker_ProcessMessage(t *testing.T) {
 					ID:                       primitive.NewObjectID(),
 					WebhookResponseMessageID: "",
 					Content:                  "Test message",
-					RecipientPhoneNumber:     "+1234567890",
+					RecipientPhoneNumber:     "+12025550123",
 					Status:                   "processing",
 					CreatedAt:                time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
 					SentAt:                   time.Date(2023, 10, 1, 0, 0, 10, 0, time.UTC),
 				}
 
-				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any()).Return(message, nil)
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
 
 				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), &client.WebhookRequest{
 					To:      message.RecipientPhoneNumber,
@@ -58,6 +78,56 @@ func TestWorker_ProcessMessage(t *testing.T) {
 					MessageID: "webhook-message-id",
 				}, nil)
 
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).DoAndReturn(func(_ context.Context, _ primitive.ObjectID, attempt DeliveryAttempt) error {
+					assert.Equal(t, "1234567890abcdef12345678", attempt.WorkerID)
+					assert.Equal(t, 202, attempt.StatusCode)
+					assert.Equal(t, "webhook-message-id", attempt.ProviderMessageID)
+					assert.Empty(t, attempt.Error)
+					return nil
+				})
+
+				mockCache.EXPECT().Set(gomock.Any(), sentRecordKey(message.ID), "webhook-message-id").Return(nil)
+
+				mockRepo.EXPECT().MarkAsSent(gomock.Any(), message.ID, "webhook-message-id").Return(nil)
+
+				mockCache.EXPECT().Set(gomock.Any(), "webhook-message-id", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:        "history write failure does not fail delivery",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(&client.WebhookResponse{
+					Message:   "Accepted",
+					MessageID: "webhook-message-id",
+				}, nil)
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(assert.AnError)
+
+				mockCache.EXPECT().Set(gomock.Any(), sentRecordKey(message.ID), "webhook-message-id").Return(nil)
+
 				mockRepo.EXPECT().MarkAsSent(gomock.Any(), message.ID, "webhook-message-id").Return(nil)
 
 				mockCache.EXPECT().Set(gomock.Any(), "webhook-message-id", gomock.Any()).Return(nil)
@@ -73,20 +143,541 @@ func TestWorker_ProcessMessage(t *testing.T) {
 					ID:                       primitive.NewObjectID(),
 					WebhookResponseMessageID: "",
 					Content:                  "Test message",
-					RecipientPhoneNumber:     "+1234567890",
+					RecipientPhoneNumber:     "+12025550123",
 					Status:                   "processing",
+					Attempts:                 3,
 					CreatedAt:                time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
 					SentAt:                   time.Date(2023, 10, 1, 0, 0, 10, 0, time.UTC),
 				}
 
-				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any()).Return(message, nil)
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
 
 				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), &client.WebhookRequest{
 					To:      message.RecipientPhoneNumber,
 					Content: message.Content,
-				}).Return(nil, assert.AnError)
+				}).Return(nil, &client.WebhookError{StatusCode: 503, Retryable: true})
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).DoAndReturn(func(_ context.Context, _ primitive.ObjectID, attempt DeliveryAttempt) error {
+					assert.Equal(t, 503, attempt.StatusCode)
+					assert.Equal(t, "failed to post message, status code: 503", attempt.Error)
+					assert.Empty(t, attempt.ProviderMessageID)
+					return nil
+				})
+
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(message.ID)).Return(nil)
+
+				mockRepo.EXPECT().MarkAsFailed(gomock.Any(), message, DeliveryFailure{
+					Reason:     "failed to send webhook: failed to post message, status code: 503",
+					Class:      FailureClassRetryable,
+					StatusCode: 503,
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "failed message is retried while attempts remain",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					Attempts:             1,
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(nil, &client.WebhookError{StatusCode: 503, RetryAfter: time.Hour, Retryable: true})
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(message.ID)).Return(nil)
+
+				mockRepo.EXPECT().ScheduleRetry(gomock.Any(), message, gomock.Any(), DeliveryFailure{
+					Reason:     "failed to send webhook: failed to post message, status code: 503",
+					Class:      FailureClassRetryable,
+					StatusCode: 503,
+				}).DoAndReturn(func(_ context.Context, _ *Message, nextAttemptAt time.Time, _ DeliveryFailure) error {
+					assert.True(t, nextAttemptAt.After(time.Now().Add(59*time.Minute)), "Retry-After should override shorter backoff")
+					return nil
+				})
+			},
+		},
+		{
+			name:        "retry after a lost lease leaves the message to its new holder",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					WorkerID:             "1234567890abcdef12345678",
+					Attempts:             1,
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(nil, &client.WebhookError{StatusCode: 503, Retryable: true})
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(message.ID)).Return(nil)
+
+				mockRepo.EXPECT().ScheduleRetry(gomock.Any(), message, gomock.Any(), gomock.Any()).Return(mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:        "suppression after a lost lease leaves the message to its new holder",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					WorkerID:             "1234567890abcdef12345678",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
 
-				mockRepo.EXPECT().MarkAsFailed(gomock.Any(), message.ID, "failed to send webhook: assert.AnError general error for testing").Return(nil)
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("STOP keyword", nil)
+
+				mockRepo.EXPECT().MarkAsSuppressed(gomock.Any(), message, "recipient suppressed: STOP keyword").Return(mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:        "permanent webhook failure is not retried",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					Attempts:             1,
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(nil, &client.WebhookError{StatusCode: 400, Body: "invalid phone number", Retryable: false})
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(message.ID)).Return(nil)
+
+				mockRepo.EXPECT().MarkAsFailed(gomock.Any(), message, DeliveryFailure{
+					Reason:     "failed to send webhook: failed to post message, status code: 400",
+					Class:      FailureClassPermanent,
+					StatusCode: 400,
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "webhook timeout keeps the send guard and marks message as stuck",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					Attempts:             1,
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(nil, &client.WebhookError{Retryable: true, Err: context.DeadlineExceeded})
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+
+				mockRepo.EXPECT().MarkAsStuck(gomock.Any(), message, "webhook call may have delivered the message: failed to post message: context deadline exceeded").Return(nil)
+			},
+		},
+		{
+			name:        "connection failure releases the send guard and is retried",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					Attempts:             1,
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "1234567890abcdef12345678", 24*time.Hour).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(nil, &client.WebhookError{Retryable: true, NotSent: true, Err: errors.New("connection refused")})
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(message.ID)).Return(nil)
+
+				mockRepo.EXPECT().ScheduleRetry(gomock.Any(), message, gomock.Any(), DeliveryFailure{
+					Reason: "failed to send webhook: failed to post message: connection refused",
+					Class:  FailureClassRetryable,
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "dispatch marker failure does not call webhook",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(assert.AnError)
+			},
+		},
+		{
+			name:        "lost lease skips the webhook call",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					WorkerID:             "1234567890abcdef12345678",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:        "earlier send is reconciled instead of posting again",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("webhook-message-id", nil)
+
+				mockRepo.EXPECT().MarkAsSent(gomock.Any(), message.ID, "webhook-message-id").Return(nil)
+			},
+		},
+		{
+			name:        "held send guard marks message as stuck",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), gomock.Any(), gomock.Any()).Return(false, nil)
+
+				mockRepo.EXPECT().MarkAsStuck(gomock.Any(), message, "send guard held by an earlier attempt").Return(nil)
+			},
+		},
+		{
+			name:        "content matching a filter rule marks message as invalid content",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Reset your password at https://intranet.example.local",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("internal-url", true)
+
+				mockRepo.EXPECT().MarkAsInvalidContent(gomock.Any(), message, "content matched filter rule internal-url").Return(nil)
+			},
+		},
+		{
+			name:        "suppressed recipient is not sent",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("STOP keyword", nil)
+
+				mockRepo.EXPECT().MarkAsSuppressed(gomock.Any(), message, "recipient suppressed: STOP keyword").Return(nil)
+			},
+		},
+		{
+			name:        "suppression lookup error does not call webhook",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", assert.AnError)
+			},
+		},
+		{
+			name:        "send guard error retries the message without calling webhook",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     true,
+			wantProcess: true,
+			beforeSuite: func() {
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Test message",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), gomock.Any(), gomock.Any()).Return(false, assert.AnError)
+
+				mockRepo.EXPECT().ScheduleRetry(gomock.Any(), message, gomock.Any(), DeliveryFailure{
+					Reason: "failed to acquire send guard: " + assert.AnError.Error(),
+					Class:  FailureClassRetryable,
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "expired message is not sent",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				expiresAt := time.Now().Add(-time.Minute)
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Your verification code is: 729384",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Now().Add(-time.Hour),
+					ExpiresAt:            &expiresAt,
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockRepo.EXPECT().MarkAsExpired(gomock.Any(), message).Return(nil)
+			},
+		},
+		{
+			name:        "message outside its delivery window is deferred",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				now := time.Now().UTC()
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Spring sale starts today!",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					DeliveryWindow: &DeliveryWindow{
+						Timezone: "UTC",
+						Start:    now.Add(2 * time.Hour).Format("15:04"),
+						End:      now.Add(3 * time.Hour).Format("15:04"),
+					},
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockRepo.EXPECT().DeferMessage(gomock.Any(), message, gomock.Any()).DoAndReturn(func(_ context.Context, _ *Message, until time.Time) error {
+					assert.True(t, until.After(now.Add(time.Hour)))
+					return nil
+				})
+			},
+		},
+		{
+			name:        "message whose delivery window opens after expiry is expired",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				now := time.Now().UTC()
+				expiresAt := now.Add(time.Hour)
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					Content:              "Your verification code is: 729384",
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					ExpiresAt:            &expiresAt,
+					DeliveryWindow: &DeliveryWindow{
+						Timezone: "UTC",
+						Start:    now.Add(2 * time.Hour).Format("15:04"),
+						End:      now.Add(3 * time.Hour).Format("15:04"),
+					},
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockRepo.EXPECT().MarkAsExpired(gomock.Any(), message).Return(nil)
 			},
 		},
 		{
@@ -95,7 +686,135 @@ func TestWorker_ProcessMessage(t *testing.T) {
 			wantErr:     false,
 			wantProcess: false,
 			beforeSuite: func() {
-				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any()).Return(nil, mongo.ErrNoDocuments)
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(nil, mongo.ErrNoDocuments)
+			},
+		},
+		{
+			name:        "template message is rendered before sending",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				templateID := primitive.NewObjectID()
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					TemplateID:           &templateID,
+					TemplateVariables:    map[string]string{"code": "729384"},
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+					CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockTemplateStore.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(&MessageTemplate{
+					ID:      templateID,
+					Content: "Your verification code is: {{code}}",
+				}, nil)
+
+				mockContentFilter.EXPECT().Match("Your verification code is: 729384").Return("", false)
+
+				mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+
+				mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), gomock.Any(), gomock.Any()).Return(true, nil)
+
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).DoAndReturn(func(_ context.Context, dispatched *Message) error {
+					assert.Equal(t, "Your verification code is: 729384", dispatched.Content)
+					assert.Equal(t, EncodingGSM7, dispatched.Encoding)
+					assert.Equal(t, 1, dispatched.Segments)
+					return nil
+				})
+
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), &client.WebhookRequest{
+					To:      message.RecipientPhoneNumber,
+					Content: "Your verification code is: 729384",
+				}).Return(&client.WebhookResponse{
+					Message:   "Accepted",
+					MessageID: "webhook-message-id",
+				}, nil)
+
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+
+				mockCache.EXPECT().Set(gomock.Any(), sentRecordKey(message.ID), "webhook-message-id").Return(nil)
+
+				mockRepo.EXPECT().MarkAsSent(gomock.Any(), message.ID, "webhook-message-id").Return(nil)
+
+				mockCache.EXPECT().Set(gomock.Any(), "webhook-message-id", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:        "missing template variable marks message as invalid content",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				templateID := primitive.NewObjectID()
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					TemplateID:           &templateID,
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockTemplateStore.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(&MessageTemplate{
+					ID:      templateID,
+					Content: "Your verification code is: {{code}}",
+				}, nil)
+
+				mockRepo.EXPECT().MarkAsInvalidContent(gomock.Any(), message, "missing template variables: code").Return(nil)
+			},
+		},
+		{
+			name:        "deleted template marks message as invalid content",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				templateID := primitive.NewObjectID()
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					TemplateID:           &templateID,
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockTemplateStore.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(nil, mongo.ErrNoDocuments)
+
+				mockRepo.EXPECT().MarkAsInvalidContent(gomock.Any(), message, "template not found").Return(nil)
+			},
+		},
+		{
+			name:        "rendered template exceeding content length marks message as invalid content",
+			messageID:   "1234567890abcdef12345678",
+			wantErr:     false,
+			wantProcess: true,
+			beforeSuite: func() {
+				templateID := primitive.NewObjectID()
+				message := &Message{
+					ID:                   primitive.NewObjectID(),
+					TemplateID:           &templateID,
+					TemplateVariables:    map[string]string{"name": strings.Repeat("a", 160)},
+					RecipientPhoneNumber: "+12025550123",
+					Status:               "processing",
+				}
+
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+
+				mockTemplateStore.EXPECT().RetrieveTemplate(gomock.Any(), templateID).Return(&MessageTemplate{
+					ID:      templateID,
+					Content: "Hello {{name}}",
+				}, nil)
+
+				mockRepo.EXPECT().MarkAsInvalidContent(gomock.Any(), message, gomock.Any()).DoAndReturn(func(_ context.Context, _ *Message, reason string) error {
+					assert.True(t, strings.HasPrefix(reason, "rendered template is invalid: "))
+					return nil
+				})
 			},
 		},
 		{
@@ -108,15 +827,18 @@ func TestWorker_ProcessMessage(t *testing.T) {
 					ID:                       primitive.NewObjectID(),
 					WebhookResponseMessageID: "",
 					Content:                  "Test message with content length exceeding the limit 160 characters. Lorem ipsum dolor sit amet, consectetur adipiscing elit. Sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. ",
-					RecipientPhoneNumber:     "+1234567890",
+					RecipientPhoneNumber:     "+12025550123",
 					Status:                   "processing",
 					CreatedAt:                time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
 					SentAt:                   time.Date(2023, 10, 1, 0, 0, 10, 0, time.UTC),
 				}
 
-				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any()).Return(message, nil)
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
 
-				mockRepo.EXPECT().MarkAsFailed(gomock.Any(), message.ID, "invalid message struct: Key: 'Message.Content' Error:Field validation for 'Content' failed on the 'max' tag").Return(nil)
+				mockRepo.EXPECT().MarkAsFailed(gomock.Any(), message, DeliveryFailure{
+					Reason: "invalid message struct: Key: 'Message.Content' Error:Field validation for 'Content' failed on the 'segments' tag",
+					Class:  FailureClassInvalid,
+				}).Return(nil)
 			},
 		},
 	}
@@ -124,10 +846,335 @@ func TestWorker_ProcessMessage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.beforeSuite()
-			worker := NewWorkerInstance(tt.messageID, mockRepo, mockWebhookClient, mockCache, config, zap.NewNop(), validator.New())
+			worker := NewWorkerInstance(tt.messageID, mockRepo, mockTemplateStore, mockSuppressionList, mockContentFilter, mockWebhookClient, mockCache, config, zap.NewNop(), newTestValidator())
 			process, err := worker.ProcessMessage(context.Background())
 			assert.Equal(t, tt.wantErr, err != nil)
 			assert.Equal(t, tt.wantProcess, process)
 		})
 	}
 }
+
+func TestWorker_ProcessMessageDuplicate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWorkerMessageStore(ctrl)
+	mockWebhookClient := NewMockWebhookClient(ctrl)
+	mockCache := NewMockWorkerMessageCache(ctrl)
+	mockSuppressionList := NewMockWorkerSuppressionList(ctrl)
+	mockContentFilter := NewMockWorkerContentFilter(ctrl)
+	config := WorkerConfig{
+		Retry:        RetryPolicy{MaxAttempts: 3},
+		SendGuardTTL: 24 * time.Hour,
+		DedupeWindow: 10 * time.Minute,
+	}
+
+	newMessage := func() *Message {
+		message := &Message{
+			ID:                   primitive.NewObjectID(),
+			Content:              "Test message",
+			RecipientPhoneNumber: "+12025550123",
+			Status:               "processing",
+			CreatedAt:            time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), gomock.Any(), "").Return(message, nil)
+		mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+		mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+		mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+		return message
+	}
+
+	expectSent := func(message *Message) {
+		mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "worker-1", 24*time.Hour).Return(true, nil)
+		mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+		mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(&client.WebhookResponse{MessageID: "webhook-message-id"}, nil)
+		mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+		mockCache.EXPECT().Set(gomock.Any(), sentRecordKey(message.ID), "webhook-message-id").Return(nil)
+		mockRepo.EXPECT().MarkAsSent(gomock.Any(), message.ID, "webhook-message-id").Return(nil)
+		mockCache.EXPECT().SetWithTTL(gomock.Any(), dedupeKey(message.RecipientPhoneNumber, message.Content), message.ID.Hex(), 10*time.Minute).Return(nil)
+		mockCache.EXPECT().Set(gomock.Any(), "webhook-message-id", gomock.Any()).Return(nil)
+	}
+
+	key := dedupeKey("+12025550123", "Test message")
+
+	tests := []struct {
+		name        string
+		wantErr     bool
+		beforeSuite func()
+	}{
+		{
+			name: "first message takes the dedupe key and is sent",
+			beforeSuite: func() {
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(true, nil)
+				expectSent(message)
+			},
+		},
+		{
+			name: "duplicate of a sent message is linked to the original",
+			beforeSuite: func() {
+				originalID := primitive.NewObjectID()
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(originalID.Hex(), nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), originalID).Return(&Message{ID: originalID, Status: StatusSent}, nil)
+				mockRepo.EXPECT().MarkAsDuplicate(gomock.Any(), message, originalID).Return(nil)
+			},
+		},
+		{
+			name: "duplicate of a pending message is deferred",
+			beforeSuite: func() {
+				originalID := primitive.NewObjectID()
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(originalID.Hex(), nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), originalID).Return(&Message{ID: originalID, Status: StatusProcessing}, nil)
+				mockRepo.EXPECT().DeferMessage(gomock.Any(), message, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "duplicate of a stuck message is deferred",
+			beforeSuite: func() {
+				originalID := primitive.NewObjectID()
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(originalID.Hex(), nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), originalID).Return(&Message{ID: originalID, Status: StatusStuck}, nil)
+				mockRepo.EXPECT().DeferMessage(gomock.Any(), message, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "duplicate of an expired message takes over the dedupe key and is sent",
+			beforeSuite: func() {
+				originalID := primitive.NewObjectID()
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(originalID.Hex(), nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), originalID).Return(&Message{ID: originalID, Status: StatusExpired}, nil)
+				mockCache.EXPECT().Del(gomock.Any(), key).Return(nil)
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(true, nil)
+				expectSent(message)
+			},
+		},
+		{
+			name: "duplicate of a cancelled message takes over the dedupe key and is sent",
+			beforeSuite: func() {
+				originalID := primitive.NewObjectID()
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(originalID.Hex(), nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), originalID).Return(&Message{ID: originalID, Status: StatusCancelled}, nil)
+				mockCache.EXPECT().Del(gomock.Any(), key).Return(nil)
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(true, nil)
+				expectSent(message)
+			},
+		},
+		{
+			name: "duplicate losing the dedupe key takeover to another duplicate is deferred",
+			beforeSuite: func() {
+				originalID := primitive.NewObjectID()
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(originalID.Hex(), nil)
+				mockRepo.EXPECT().RetrieveMessage(gomock.Any(), originalID).Return(&Message{ID: originalID, Status: StatusFailed}, nil)
+				mockCache.EXPECT().Del(gomock.Any(), key).Return(nil)
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockRepo.EXPECT().DeferMessage(gomock.Any(), message, gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "retry of the original message is sent",
+			beforeSuite: func() {
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, nil)
+				mockCache.EXPECT().Get(gomock.Any(), key).Return(message.ID.Hex(), nil)
+				expectSent(message)
+			},
+		},
+		{
+			name:    "dedupe key error does not call webhook",
+			wantErr: true,
+			beforeSuite: func() {
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(false, assert.AnError)
+			},
+		},
+		{
+			name:    "permanent failure releases the dedupe key",
+			wantErr: true,
+			beforeSuite: func() {
+				message := newMessage()
+				mockCache.EXPECT().SetNX(gomock.Any(), key, message.ID.Hex(), 10*time.Minute).Return(true, nil)
+				mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "worker-1", 24*time.Hour).Return(true, nil)
+				mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+				mockWebhookClient.EXPECT().PostMessage(gomock.Any(), gomock.Any()).Return(nil, &client.WebhookError{StatusCode: 400, Retryable: false})
+				mockRepo.EXPECT().RecordAttempt(gomock.Any(), message.ID, gomock.Any()).Return(nil)
+				mockCache.EXPECT().Del(gomock.Any(), sendGuardKey(message.ID)).Return(nil)
+				mockRepo.EXPECT().MarkAsFailed(gomock.Any(), message, gomock.Any()).Return(nil)
+				mockCache.EXPECT().Del(gomock.Any(), key).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			worker := NewWorkerInstance("worker-1", mockRepo, NewMockWorkerTemplateStore(ctrl), mockSuppressionList, mockContentFilter, mockWebhookClient, mockCache, config, zap.NewNop(), newTestValidator())
+			process, err := worker.ProcessMessage(context.Background())
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.True(t, process)
+		})
+	}
+}
+
+func TestWorker_ClaimMessagePriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWorkerMessageStore(ctrl)
+	config := WorkerConfig{LowPriorityShare: 0.5}
+	worker := NewWorkerInstance("worker-1", mockRepo, NewMockWorkerTemplateStore(ctrl), NewMockWorkerSuppressionList(ctrl), NewMockWorkerContentFilter(ctrl), NewMockWebhookClient(ctrl), NewMockWorkerMessageCache(ctrl), config, zap.NewNop(), newTestValidator())
+
+	normal := &Message{ID: primitive.NewObjectID(), Priority: PriorityNormal}
+	low := &Message{ID: primitive.NewObjectID(), Priority: PriorityLow}
+
+	tests := []struct {
+		name        string
+		want        *Message
+		wantErr     error
+		beforeSuite func()
+	}{
+		{
+			name:    "regular claim uses priority order",
+			want:    normal,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", "").Return(normal, nil)
+			},
+		},
+		{
+			name:    "guarded claim prefers low priority",
+			want:    low,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", PriorityLow).Return(low, nil)
+			},
+		},
+		{
+			name:    "regular claim after guarded claim",
+			want:    normal,
+			wantErr: nil,
+			beforeSuite: func() {
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", "").Return(normal, nil)
+			},
+		},
+		{
+			name:    "guarded claim falls back when no low priority message is due",
+			want:    normal,
+			wantErr: nil,
+			beforeSuite: func() {
+				gomock.InOrder(
+					mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", PriorityLow).Return(nil, mongo.ErrNoDocuments),
+					mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", "").Return(normal, nil),
+				)
+			},
+		},
+		{
+			name:    "fetch errors are returned",
+			want:    nil,
+			wantErr: assert.AnError,
+			beforeSuite: func() {
+				mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", "").Return(nil, assert.AnError)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beforeSuite()
+			got, err := worker.claimMessage(context.Background())
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorker_SendGuardTTLDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWorkerMessageStore(ctrl)
+	mockCache := NewMockWorkerMessageCache(ctrl)
+	mockSuppressionList := NewMockWorkerSuppressionList(ctrl)
+	mockContentFilter := NewMockWorkerContentFilter(ctrl)
+
+	message := &Message{
+		ID:                   primitive.NewObjectID(),
+		Content:              "Test message",
+		RecipientPhoneNumber: "+12025550123",
+		Status:               StatusProcessing,
+		WorkerID:             "worker-1",
+	}
+
+	mockRepo.EXPECT().FetchAndMarkProcessing(gomock.Any(), "worker-1", "").Return(message, nil)
+	mockContentFilter.EXPECT().Match(message.Content).Return("", false)
+	mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(message.ID)).Return("", nil)
+	mockSuppressionList.EXPECT().SuppressionReason(gomock.Any(), message.RecipientPhoneNumber).Return("", nil)
+	mockRepo.EXPECT().MarkAsDispatching(gomock.Any(), message).Return(nil)
+	// Without a configured TTL the guard still expires, instead of being kept forever.
+	mockCache.EXPECT().SetNX(gomock.Any(), sendGuardKey(message.ID), "worker-1", defaultSendGuardTTL).Return(false, nil)
+	mockRepo.EXPECT().MarkAsStuck(gomock.Any(), message, "send guard held by an earlier attempt").Return(nil)
+
+	worker := NewWorkerInstance("worker-1", mockRepo, NewMockWorkerTemplateStore(ctrl), mockSuppressionList, mockContentFilter, NewMockWebhookClient(ctrl), mockCache, WorkerConfig{}, zap.NewNop(), newTestValidator())
+	process, err := worker.ProcessMessage(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, process)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "first attempt waits base delay",
+			policy:  RetryPolicy{BaseDelay: time.Second, Multiplier: 2},
+			attempt: 1,
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+		{
+			name:    "delay grows exponentially",
+			policy:  RetryPolicy{BaseDelay: time.Second, Multiplier: 2},
+			attempt: 4,
+			wantMin: 8 * time.Second,
+			wantMax: 8 * time.Second,
+		},
+		{
+			name:    "multiplier below one keeps delay constant",
+			policy:  RetryPolicy{BaseDelay: time.Second, Multiplier: 0},
+			attempt: 3,
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+		{
+			name:    "jitter stays within configured fraction",
+			policy:  RetryPolicy{BaseDelay: 10 * time.Second, Multiplier: 1, Jitter: 0.2},
+			attempt: 2,
+			wantMin: 8 * time.Second,
+			wantMax: 12 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.Backoff(tt.attempt)
+			assert.GreaterOrEqual(t, got, tt.wantMin)
+			assert.LessOrEqual(t, got, tt.wantMax)
+		})
+	}
+}
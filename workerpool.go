@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
@@ -20,6 +22,18 @@ type PoolConfig struct {
 	NumWorkers      int           `mapstructure:"numWorkers"`
 	Timeout         time.Duration `mapstructure:"timeout"`
 	InitialJobFetch bool          `mapstructure:"initialJobFetch"`
+	LeaseDuration   time.Duration `mapstructure:"leaseDuration"`
+	ReaperInterval  time.Duration `mapstructure:"reaperInterval"`
+	// ExpirySweepInterval is how often unsent messages past their expires_at
+	// are expired in bulk. Zero disables the sweeper.
+	ExpirySweepInterval time.Duration `mapstructure:"expirySweepInterval"`
+}
+
+type WorkerPoolMessageStore interface {
+	WorkerMessageStore
+	ReleaseExpiredLeases(ctx context.Context, expiredBefore time.Time) (int64, error)
+	RetrieveExpiredDispatches(ctx context.Context, expiredBefore time.Time) ([]Message, error)
+	ExpireMessages(ctx context.Context, now time.Time) (int64, error)
 }
 
 type WorkerPoolImpl struct {
@@ -34,16 +48,22 @@ type WorkerPoolImpl struct {
 
 	rateLimiter *RateLimiter
 
-	workerMessageStore WorkerMessageStore
-	webhookClient      WebhookClient
-	workerMessageCache WorkerMessageCache
-	appConfig          Config
-	validate           *validator.Validate
+	workerMessageStore  WorkerPoolMessageStore
+	workerTemplateStore WorkerTemplateStore
+	suppressionList     WorkerSuppressionList
+	contentFilter       WorkerContentFilter
+	webhookClient       WebhookClient
+	workerMessageCache  WorkerMessageCache
+	appConfig           Config
+	validate            *validator.Validate
 }
 
 func NewWorkerPool(
 	numWorkers int,
-	store WorkerMessageStore,
+	store WorkerPoolMessageStore,
+	templateStore WorkerTemplateStore,
+	suppressionList WorkerSuppressionList,
+	contentFilter WorkerContentFilter,
 	whClient WebhookClient,
 	cache WorkerMessageCache,
 	cfg Config,
@@ -56,18 +76,21 @@ func NewWorkerPool(
 	ctx, cancel := context.WithCancel(context.Background())
 
 	pool := &WorkerPoolImpl{
-		numWorkers:         numWorkers,
-		logger:             logger.With(zap.String("component", "workerpool")),
-		poolCtx:            ctx,
-		poolCancel:         cancel,
-		workerMessageStore: store,
-		webhookClient:      whClient,
-		workerMessageCache: cache,
-		appConfig:          cfg,
-		canFetchNewJobs:    canFetchNewJobsInitial,
-		wg:                 wg,
-		validate:           validate,
-		rateLimiter:        rateLimiter,
+		numWorkers:          numWorkers,
+		logger:              logger.With(zap.String("component", "workerpool")),
+		poolCtx:             ctx,
+		poolCancel:          cancel,
+		workerMessageStore:  store,
+		workerTemplateStore: templateStore,
+		suppressionList:     suppressionList,
+		contentFilter:       contentFilter,
+		webhookClient:       whClient,
+		workerMessageCache:  cache,
+		appConfig:           cfg,
+		canFetchNewJobs:     canFetchNewJobsInitial,
+		wg:                  wg,
+		validate:            validate,
+		rateLimiter:         rateLimiter,
 	}
 
 	return pool
@@ -85,6 +108,9 @@ func (p *WorkerPoolImpl) Start() {
 		instance := NewWorkerInstance(
 			workerID,
 			p.workerMessageStore,
+			p.workerTemplateStore,
+			p.suppressionList,
+			p.contentFilter,
 			p.webhookClient,
 			p.workerMessageCache,
 			p.appConfig.Worker,
@@ -106,6 +132,122 @@ func (p *WorkerPoolImpl) Start() {
 
 		go instance.Start(p.poolCtx, p.wg, canProcessFunc)
 	}
+
+	if p.appConfig.Pool.LeaseDuration > 0 && p.appConfig.Pool.ReaperInterval > 0 {
+		p.wg.Add(1)
+		go p.runReaper()
+	}
+
+	if p.appConfig.Pool.ExpirySweepInterval > 0 {
+		p.wg.Add(1)
+		go p.runExpirySweeper()
+	}
+}
+
+// runReaper periodically recovers messages whose processing lease expired,
+// e.g. because the worker holding them crashed.
+func (p *WorkerPoolImpl) runReaper() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.appConfig.Pool.ReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.poolCtx.Done():
+			p.logger.Info("Lease reaper stopped")
+			return
+		case <-ticker.C:
+			p.reapExpiredLeases(p.poolCtx, time.Now().Add(-p.appConfig.Pool.LeaseDuration))
+		}
+	}
+}
+
+// reapExpiredLeases recovers the messages whose lease expired before
+// expiredBefore. Messages whose webhook call never started go back to unsent.
+// A message whose webhook call started is marked as sent when the worker left
+// a sent record, e.g. because MarkAsSent failed after the webhook accepted it,
+// and is marked stuck otherwise, as it may have been delivered.
+func (p *WorkerPoolImpl) reapExpiredLeases(ctx context.Context, expiredBefore time.Time) {
+	released, err := p.workerMessageStore.ReleaseExpiredLeases(ctx, expiredBefore)
+	if err != nil {
+		p.logger.Error("Failed to release expired leases", zap.Error(err))
+	}
+
+	dispatches, err := p.workerMessageStore.RetrieveExpiredDispatches(ctx, expiredBefore)
+	if err != nil {
+		p.logger.Error("Failed to retrieve expired dispatches", zap.Error(err))
+	}
+
+	var reconciled, stuck int
+	for i := range dispatches {
+		message := &dispatches[i]
+
+		providerMessageID, err := p.workerMessageCache.Get(ctx, sentRecordKey(message.ID))
+		if err != nil {
+			// Left in processing; the next run looks the sent record up again.
+			p.logger.Error("Failed to look up sent record",
+				zap.String("message_id", message.ID.Hex()),
+				zap.Error(err))
+			continue
+		}
+
+		if providerMessageID != "" {
+			if err := p.workerMessageStore.MarkAsSent(ctx, message.ID, providerMessageID); err != nil {
+				p.logger.Error("Failed to mark message as sent",
+					zap.String("message_id", message.ID.Hex()),
+					zap.Error(err))
+				continue
+			}
+			reconciled++
+			continue
+		}
+
+		err = p.workerMessageStore.MarkAsStuck(ctx, message, "lease expired after the webhook call started and no send was recorded")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue // the worker finished the message meanwhile
+		}
+		if err != nil {
+			p.logger.Error("Failed to mark message as stuck",
+				zap.String("message_id", message.ID.Hex()),
+				zap.Error(err))
+			continue
+		}
+		stuck++
+	}
+
+	if released > 0 || reconciled > 0 || stuck > 0 {
+		p.logger.Warn("Expired processing leases recovered",
+			zap.Int64("released", released),
+			zap.Int("reconciled", reconciled),
+			zap.Int("stuck", stuck))
+	}
+}
+
+// runExpirySweeper periodically expires unsent messages whose expires_at has
+// passed, so they leave the queue without being claimed by a worker.
+func (p *WorkerPoolImpl) runExpirySweeper() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.appConfig.Pool.ExpirySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.poolCtx.Done():
+			p.logger.Info("Expiry sweeper stopped")
+			return
+		case <-ticker.C:
+			expired, err := p.workerMessageStore.ExpireMessages(p.poolCtx, time.Now())
+			if err != nil {
+				p.logger.Error("Failed to expire messages", zap.Error(err))
+				continue
+			}
+			if expired > 0 {
+				p.logger.Info("Expired unsent messages", zap.Int64("expired", expired))
+			}
+		}
+	}
 }
 
 func (p *WorkerPoolImpl) ResumeFetching() {
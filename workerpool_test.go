@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	gomock "go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+// leaseStore adds the reaper queries to the worker store mock.
+type leaseStore struct {
+	*MockWorkerMessageStore
+	released   int64
+	dispatches []Message
+}
+
+func (s *leaseStore) ReleaseExpiredLeases(context.Context, time.Time) (int64, error) {
+	return s.released, nil
+}
+
+func (s *leaseStore) RetrieveExpiredDispatches(context.Context, time.Time) ([]Message, error) {
+	return s.dispatches, nil
+}
+
+func (s *leaseStore) ExpireMessages(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestWorkerPool_ReapExpiredLeases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWorkerMessageStore(ctrl)
+	mockCache := NewMockWorkerMessageCache(ctrl)
+
+	delivered := Message{ID: primitive.NewObjectID(), Status: StatusProcessing, WorkerID: "worker-1"}
+	undelivered := Message{ID: primitive.NewObjectID(), Status: StatusProcessing, WorkerID: "worker-1"}
+	finished := Message{ID: primitive.NewObjectID(), Status: StatusProcessing, WorkerID: "worker-2"}
+	unknown := Message{ID: primitive.NewObjectID(), Status: StatusProcessing, WorkerID: "worker-2"}
+
+	store := &leaseStore{
+		MockWorkerMessageStore: mockRepo,
+		released:               2,
+		dispatches:             []Message{delivered, undelivered, finished, unknown},
+	}
+	pool := &WorkerPoolImpl{
+		logger:             zap.NewNop(),
+		workerMessageStore: store,
+		workerMessageCache: mockCache,
+	}
+
+	stuckReason := "lease expired after the webhook call started and no send was recorded"
+
+	mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(delivered.ID)).Return("webhook-message-id", nil)
+	mockRepo.EXPECT().MarkAsSent(gomock.Any(), delivered.ID, "webhook-message-id").Return(nil)
+
+	mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(undelivered.ID)).Return("", nil)
+	mockRepo.EXPECT().MarkAsStuck(gomock.Any(), &undelivered, stuckReason).Return(nil)
+
+	mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(finished.ID)).Return("", nil)
+	mockRepo.EXPECT().MarkAsStuck(gomock.Any(), &finished, stuckReason).Return(mongo.ErrNoDocuments)
+
+	// A failed lookup leaves the message for the next run instead of parking it.
+	mockCache.EXPECT().Get(gomock.Any(), sentRecordKey(unknown.ID)).Return("", assert.AnError)
+
+	pool.reapExpiredLeases(context.Background(), time.Now().Add(-5*time.Minute))
+}